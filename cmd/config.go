@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"fraud-scoring/internal/domain"
+	"fraud-scoring/internal/domain/application"
+	"fraud-scoring/internal/infra/env"
+	"time"
+)
+
+const (
+	defaultScoringBudget    = 5 * time.Second
+	defaultBaselineMonths   = 3
+	defaultReviewAt         = -3
+	defaultDeclineAt        = -6
+	defaultBatchConcurrency = 16
+)
+
+// NewScoringConfig reads the scoring settings from the environment, replacing the out of range ones by their default.
+func NewScoringConfig() *application.ScoringConfig {
+	cfg := &application.ScoringConfig{
+		Budget:         env.Duration("SCORING_BUDGET", defaultScoringBudget),
+		BaselineMonths: env.Int("SCORING_BASELINE_MONTHS", defaultBaselineMonths),
+		Decision: domain.DecisionPolicy{
+			ReviewAt:  env.Int("SCORING_REVIEW_THRESHOLD", defaultReviewAt),
+			DeclineAt: env.Int("SCORING_DECLINE_THRESHOLD", defaultDeclineAt),
+		},
+		BatchConcurrency: env.Int("SCORING_BATCH_CONCURRENCY", defaultBatchConcurrency),
+		Duplicates:       env.String("SCORING_DUPLICATES", application.DuplicatesSkip),
+	}
+	if cfg.BaselineMonths < 1 {
+		cfg.BaselineMonths = defaultBaselineMonths
+	}
+	if cfg.BatchConcurrency < 1 {
+		cfg.BatchConcurrency = defaultBatchConcurrency
+	}
+	if cfg.Duplicates != application.DuplicatesReemit {
+		cfg.Duplicates = application.DuplicatesSkip
+	}
+	return cfg
+}
+
+// NewTransactionComponent builds the transaction validation rules from the environment.
+func NewTransactionComponent() *domain.TransactionComponent {
+	return domain.NewTransactionComponent(
+		env.Duration("TRANSACTION_MAX_AGE", 24*time.Hour),
+		env.Float("TRANSACTION_MIN_AMOUNT", 0.01),
+		env.Float("TRANSACTION_MAX_AMOUNT", 1000000),
+		env.Strings("TRANSACTION_SUPPORTED_CURRENCIES", []string{"BRL", "USD", "EUR"}),
+	)
+}
+
+// NewCheckoutTimeParser builds the parser of the checkout times from the environment. The checkout times without zone
+// are read in CHECKOUT_TIME_ZONE, an IANA zone, in one of the CHECKOUT_TIME_LAYOUTS.
+func NewCheckoutTimeParser() (*domain.CheckoutTimeParser, error) {
+	zone := env.String("CHECKOUT_TIME_ZONE", "UTC")
+	location, err := time.LoadLocation(zone)
+	if err != nil {
+		return nil, fmt.Errorf("checkout time zone %q: %w", zone, err)
+	}
+	return domain.NewCheckoutTimeParser(
+		env.Strings("CHECKOUT_TIME_LAYOUTS", []string{"2006-01-02T15:04:05", "2006-01-02 15:04:05"}),
+		location,
+	), nil
+}
@@ -0,0 +1,112 @@
+// Command dlq inspects and redrives the messages of the dead-letter topic. It reads the Kafka settings of the service
+// from the same environment variables: KAFKA_HOST, KAFKA_PAYMENT_PROCESSING_TOPIC and KAFKA_DEAD_LETTER_TOPIC.
+//
+//	dlq inspect [-partition p] [-offset o] [-class c] [-limit n]
+//	dlq redrive [-partition p] [-offset o] [-class c] [-limit n] [-topic t] [-dry-run]
+//
+// Both print the matching dead letters as JSON lines. redrive publishes them, as they were originally received, back
+// to the topic they were first read from before any retry, or to -topic.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"fraud-scoring/internal/infra/kafka"
+	"go.uber.org/zap"
+)
+
+// errLimit stops reading once -limit dead letters matched.
+var errLimit = errors.New("limit reached")
+
+type filter struct {
+	partition int
+	offset    int64
+	class     string
+	limit     int
+}
+
+func (f *filter) register(fs *flag.FlagSet) {
+	fs.IntVar(&f.partition, "partition", -1, "only the dead letters of this partition of the dead-letter topic")
+	fs.Int64Var(&f.offset, "offset", -1, "only the dead letter at this offset, with -partition")
+	fs.StringVar(&f.class, "class", "", "only the dead letters of this error class")
+	fs.IntVar(&f.limit, "limit", 0, "stop after this many dead letters, 0 for all")
+}
+
+func (f *filter) match(dl *kafka.DeadLetter) bool {
+	return (f.partition < 0 || dl.Partition == int32(f.partition)) &&
+		(f.offset < 0 || dl.Offset == f.offset) &&
+		(f.class == "" || dl.Class == f.class)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	var f filter
+	fs := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	f.register(fs)
+	topic := fs.String("topic", "", "redrive to this topic instead of the one first read from")
+	dryRun := fs.Bool("dry-run", false, "print the dead letters that would be redriven without publishing them")
+	switch os.Args[1] {
+	case "inspect", "redrive":
+		_ = fs.Parse(os.Args[2:])
+	default:
+		usage()
+	}
+	if f.offset >= 0 && f.partition < 0 {
+		fmt.Fprintln(os.Stderr, "-offset requires -partition")
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	sc := kafka.NewSaramaConfig()
+	producer, err := kafka.NewProducer(sc)
+	if err != nil {
+		fail(err)
+	}
+	defer producer.Close(context.Background())
+	dlq := kafka.NewDeadLetterQueue(sc, producer, zap.NewNop())
+
+	redrive := os.Args[1] == "redrive" && !*dryRun
+	out := json.NewEncoder(os.Stdout)
+	matched := 0
+	err = dlq.Read(ctx, func(dl *kafka.DeadLetter) error {
+		if !f.match(dl) {
+			return nil
+		}
+		if redrive {
+			if err := dlq.Redrive(dl, *topic); err != nil {
+				return err
+			}
+		}
+		if err := out.Encode(dl); err != nil {
+			return err
+		}
+		matched++
+		if f.limit > 0 && matched >= f.limit {
+			return errLimit
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errLimit) {
+		fail(err)
+	}
+	fmt.Fprintf(os.Stderr, "%d dead letters matched in %s\n", matched, dlq.Topic())
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: dlq inspect|redrive [-partition p] [-offset o] [-class c] [-limit n] [-topic t] [-dry-run]")
+	os.Exit(2)
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
@@ -0,0 +1,37 @@
+// Command fake-user-transactions serves UserTransactionsService from a seed file, so fraud-scoring can run locally
+// and in component tests without the real user transactions backend.
+package main
+
+import (
+	"log"
+	"net"
+
+	"fraud-scoring/internal/infra/env"
+	api "fraud-scoring/internal/infra/grpc"
+	"fraud-scoring/internal/infra/grpc/fake"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+func main() {
+	addr := env.String("FAKE_USER_TRANSACTIONS_ADDR", ":50052")
+	seedPath := env.String("FAKE_USER_TRANSACTIONS_SEED", "cmd/fake-user-transactions/seed.json")
+	seed, err := fake.LoadSeed(seedPath)
+	if err != nil {
+		log.Fatalf("failed to load seed: %v", err)
+	}
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("failed to listen on %s: %v", addr, err)
+	}
+	srv := grpc.NewServer()
+	api.RegisterUserTransactionsServiceServer(srv, fake.NewServer(seed))
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	reflection.Register(srv)
+	log.Printf("fake user transactions service listening on %s with %d users from %s", lis.Addr(), len(seed.Users), seedPath)
+	if err := srv.Serve(lis); err != nil {
+		log.Fatalf("failed to serve: %v", err)
+	}
+}
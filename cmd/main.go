@@ -1,9 +1,23 @@
 package main
 
+import (
+	"fmt"
+	"os"
+)
+
+// Exit codes, so the orchestrator can tell a bad configuration from a failure at runtime.
+const (
+	exitFailure = 1
+	exitConfig  = 2
+)
+
 func main() {
 	mngr, err := buildAppContainer()
 	if err != nil {
-		panic(err)
+		fmt.Fprintf(os.Stderr, "failed to build the application: %v\n", err)
+		os.Exit(exitConfig)
+	}
+	if err := mngr.Start(); err != nil {
+		os.Exit(exitFailure)
 	}
-	mngr.Start()
 }
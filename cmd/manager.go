@@ -2,26 +2,145 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fraud-scoring/internal/adapter/kafka/in"
+	"fraud-scoring/internal/infra/env"
+	api "fraud-scoring/internal/infra/grpc"
+	web "fraud-scoring/internal/infra/http"
 	"fraud-scoring/internal/infra/kafka"
+	"fraud-scoring/internal/infra/tracing"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
 )
 
+type ShutdownConfig struct {
+	// Timeout bounds the whole shutdown: draining the consumer and the servers, then closing the connections.
+	Timeout time.Duration
+}
+
 type Manager struct {
 	receiver *in.CheckoutEventReceiver
-	cli      kafka.CloudEventsReceiver
+	cli      *kafka.ConsumerGroup
+	producer *kafka.Producer
+	conn     *grpc.ClientConn
+	grpc     *api.ScoringServer
+	http     *web.Server
+	tracing  *tracing.Provider
+	config   *ShutdownConfig
+	log      *zap.Logger
 }
 
+// Start serves the gRPC and REST APIs and consumes checkout events until SIGTERM or SIGINT is received, or any of
+// them stops. It then shuts the service down and returns why it stopped, nil after a signal and a clean shutdown.
 func (m *Manager) Start() error {
-	err := m.cli.StartReceiver(context.Background(), m.receiver.Handle)
-	if err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, 3)
+	go func() {
+		errs <- m.grpc.Serve()
+	}()
+	go func() {
+		errs <- m.http.Serve()
+	}()
+	go func() {
+		errs <- m.cli.StartReceiver(ctx, m.receiver.Handle, m.receiver.OrderingKey)
+	}()
+
+	var err error
+	select {
+	case <-ctx.Done():
+		m.log.Info("shutting down on signal")
+	case err = <-errs:
+		if err == nil {
+			err = errors.New("stopped unexpectedly")
+		}
+		m.log.Error("shutting down after a component stopped", zap.Error(err))
+	}
+	// A second signal kills the process right away.
+	stop()
+	cancel()
+	return errors.Join(err, m.shutdown())
+}
+
+// shutdown stops taking work, waits for the work in flight and closes the connections, in that order, within the
+// shutdown timeout.
+func (m *Manager) shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.config.Timeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	var errs []error
+	collect := func(err error) {
+		if err != nil {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		}
+	}
+	var wg sync.WaitGroup
+	for _, drain := range []func(context.Context) error{m.cli.Drain, m.http.Shutdown, m.grpc.Shutdown} {
+		wg.Add(1)
+		go func(drain func(context.Context) error) {
+			defer wg.Done()
+			collect(drain(ctx))
+		}(drain)
+	}
+	wg.Wait()
+
+	// The score cards of the drained messages are published by now, nothing uses the connections anymore.
+	collect(m.cli.Close())
+	collect(m.producer.Close(ctx))
+	collect(m.conn.Close())
+	collect(m.flushTraces())
+	if err := errors.Join(errs...); err != nil {
+		m.log.Error("shutdown did not complete cleanly", zap.Error(err))
 		return err
 	}
+	m.log.Info("shutdown complete")
 	return nil
 }
 
-func NewManager(receiver *in.CheckoutEventReceiver, cli kafka.CloudEventsReceiver) *Manager {
+// flushTraces exports the spans still buffered before the process exits. It has its own timeout, so the spans of a
+// shutdown that ran out of time are exported too.
+func (m *Manager) flushTraces() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return m.tracing.Shutdown(ctx)
+}
+
+func NewManager(
+	receiver *in.CheckoutEventReceiver,
+	cli *kafka.ConsumerGroup,
+	producer *kafka.Producer,
+	conn *grpc.ClientConn,
+	grpc *api.ScoringServer,
+	http *web.Server,
+	tracing *tracing.Provider,
+	config *ShutdownConfig,
+	log *zap.Logger,
+) *Manager {
 	return &Manager{
 		receiver: receiver,
 		cli:      cli,
+		producer: producer,
+		conn:     conn,
+		grpc:     grpc,
+		http:     http,
+		tracing:  tracing,
+		config:   config,
+		log:      log,
 	}
 }
+
+func NewShutdownConfig() *ShutdownConfig {
+	return &ShutdownConfig{Timeout: env.Duration("SHUTDOWN_TIMEOUT", 30*time.Second)}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	grpcout "fraud-scoring/internal/adapter/grpc/out"
+	hout "fraud-scoring/internal/adapter/history/out"
+	httpin "fraud-scoring/internal/adapter/http/in"
+	"fraud-scoring/internal/adapter/kafka/out"
+	"fraud-scoring/internal/domain/history"
+	api "fraud-scoring/internal/infra/grpc"
+	"fraud-scoring/internal/infra/health"
+	"fraud-scoring/internal/infra/kafka"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// NewUserTransactionsChain puts the remote user transactions service in the history fallback chain.
+func NewUserTransactionsChain(
+	config *hout.HistoryConfig,
+	remote *grpcout.GrpcUserTransactionsRepository,
+	cache *hout.CachedUserTransactions,
+	local *hout.LocalUserTransactionsStore,
+	defaults *hout.DefaultUserTransactions,
+	log *zap.Logger,
+) (*hout.ChainUserTransactionsRepository, error) {
+	return hout.NewChainUserTransactionsRepository(config, remote, cache, local, defaults, log)
+}
+
+// NewUserTransactionsHandler serves the history endpoints through the configured providers but the defaults, which
+// would make up a history for buyers without one. With the defaults alone, the endpoints answer 404 for every buyer
+// rather than keeping the service from starting.
+func NewUserTransactionsHandler(
+	config *hout.HistoryConfig,
+	remote *grpcout.GrpcUserTransactionsRepository,
+	cache *hout.CachedUserTransactions,
+	local *hout.LocalUserTransactionsStore,
+	log *zap.Logger,
+) (*httpin.UserTransactionsHandler, error) {
+	lookups := *config
+	lookups.Providers = nil
+	for _, name := range config.Providers {
+		if name != history.SourceDefault {
+			lookups.Providers = append(lookups.Providers, name)
+		}
+	}
+	if len(lookups.Providers) == 0 {
+		log.Warn("no history provider but the defaults, the user transactions endpoints answer 404")
+		return httpin.NewUserTransactionsHandler(nil, log), nil
+	}
+	chain, err := hout.NewChainUserTransactionsRepository(&lookups, remote, cache, local, nil, log)
+	if err != nil {
+		return nil, err
+	}
+	return httpin.NewUserTransactionsHandler(chain, log), nil
+}
+
+// NewRecordingScoreCard publishes score cards to Kafka and records the scored transactions in the local store.
+func NewRecordingScoreCard(kafka *out.KafkaTransactionScoreCard, local *hout.LocalUserTransactionsStore) *hout.RecordingTransactionScoreCard {
+	return hout.NewRecordingTransactionScoreCard(kafka, local)
+}
+
+// NewConsumerGroup pauses the consumption while the producer can't reach the fraud detection topic, or the user
+// transactions circuit is open and no history provider falls back from it, the events would only fail through the
+// retry topics meanwhile.
+func NewConsumerGroup(
+	sc *kafka.SaramaConfig,
+	retry *kafka.Retrier,
+	dlq *kafka.DeadLetterQueue,
+	breakers *api.CircuitBreakers,
+	producer *kafka.Producer,
+	config *hout.HistoryConfig,
+	log *zap.Logger,
+) (*kafka.ConsumerGroup, error) {
+	consumer, err := kafka.NewConsumerGroup(sc, retry, dlq, log)
+	if err != nil {
+		return nil, err
+	}
+	if config.Needs(history.SourceGrpc) {
+		consumer.DependsOn("grpc", breakers.Check)
+	}
+	consumer.DependsOn("kafka_producer", producer.Check)
+	return consumer, nil
+}
+
+// NewHealthChecker registers the probes of the service dependencies. Readiness follows the consumer group membership,
+// the producer connectivity and the user transactions connection, liveness the consumption loop.
+func NewHealthChecker(config *health.Config, consumer *kafka.ConsumerGroup, producer *kafka.Producer, conn *grpc.ClientConn) *health.Checker {
+	checker := health.NewChecker(config)
+	checker.Readiness("kafka", consumer.Ready)
+	checker.Readiness("kafka", producer.Check)
+	checker.Readiness("grpc", func(context.Context) error {
+		return api.CheckConnection(conn)
+	})
+	checker.Liveness("kafka", consumer.Live)
+	return checker
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	hout "fraud-scoring/internal/adapter/history/out"
+	"fraud-scoring/internal/domain/history"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func TestNewUserTransactionsHandler_DefaultsOnly(t *testing.T) {
+	config := &hout.HistoryConfig{Providers: []string{history.SourceDefault}}
+	uth, err := NewUserTransactionsHandler(config, nil, nil, nil, zaptest.NewLogger(t))
+	if err != nil {
+		t.Fatalf("Expected the service to start with the defaults alone, got %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	uth.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/users/12345678901/transactions/last", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 without a provider to look the history up, got %d: %s", rec.Code, rec.Body)
+	}
+}
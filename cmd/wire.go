@@ -4,30 +4,76 @@
 package main
 
 import (
+	in2 "fraud-scoring/internal/adapter/grpc/in"
 	out2 "fraud-scoring/internal/adapter/grpc/out"
+	hout "fraud-scoring/internal/adapter/history/out"
+	in3 "fraud-scoring/internal/adapter/http/in"
+	iout "fraud-scoring/internal/adapter/idempotency/out"
 	"fraud-scoring/internal/adapter/kafka/in"
 	"fraud-scoring/internal/adapter/kafka/out"
 	"fraud-scoring/internal/domain/application"
 	"fraud-scoring/internal/domain/repositories"
 	api "fraud-scoring/internal/infra/grpc"
+	"fraud-scoring/internal/infra/health"
+	web "fraud-scoring/internal/infra/http"
 	ik "fraud-scoring/internal/infra/kafka"
 	"fraud-scoring/internal/infra/logger"
+	"fraud-scoring/internal/infra/tracing"
 	"github.com/google/wire"
+	"net/http"
 )
 
 func buildAppContainer() (*Manager, error) {
 	wire.Build(ik.NewSaramaConfig,
 		api.NewUserTransactionsConfig,
+		api.NewCircuitBreakers,
+		api.NewUserTransactionsConn,
 		api.NewUserTransactionGrpc,
+		ik.NewProducer,
 		ik.NewCloudEventsKafkaSender,
-		ik.NewCloudEventsKafkaConsumer,
+		ik.NewRetrier,
+		ik.NewDeadLetterQueue,
+		NewConsumerGroup,
 		out.NewKafkaTransactionScoreCard,
+		out.NewKafkaTransactionRejections,
+		wire.Bind(new(repositories.TransactionRejections), new(*out.KafkaTransactionRejections)),
 		logger.NewLogger,
 		out2.NewGrpcUserTransactionsRepository,
-		wire.Bind(new(repositories.TransactionScoreCard), new(*out.KafkaTransactionScoreCard)),
-		wire.Bind(new(repositories.UserTransactionsRepository), new(*out2.GrpcUserTransactionsRepository)),
+		hout.NewHistoryConfig,
+		hout.NewCachedUserTransactions,
+		hout.NewLocalUserTransactionsStore,
+		hout.NewDefaultUserTransactions,
+		NewUserTransactionsChain,
+		NewRecordingScoreCard,
+		wire.Bind(new(repositories.TransactionScoreCard), new(*hout.RecordingTransactionScoreCard)),
+		wire.Bind(new(repositories.UserTransactionsRepository), new(*hout.ChainUserTransactionsRepository)),
+		iout.NewIdempotencyConfig,
+		iout.NewInMemoryScoringResults,
+		wire.Bind(new(repositories.ScoringResults), new(*iout.InMemoryScoringResults)),
+		NewScoringConfig,
 		application.NewPaymentRiskScoring,
+		NewCheckoutTimeParser,
+		application.NewTransactionValidation,
+		in.NewEventRegistry,
 		in.NewCheckoutEventReceiver,
+		in2.NewGrpcScoringService,
+		wire.Bind(new(api.ScoringServiceServer), new(*in2.GrpcScoringService)),
+		api.NewServerConfig,
+		api.NewScoringServer,
+		NewTransactionComponent,
+		in3.NewScoreTransactionHandler,
+		in3.NewBatchScoreHandler,
+		NewUserTransactionsHandler,
+		health.NewConfig,
+		NewHealthChecker,
+		in3.NewHealthHandler,
+		in3.NewRouter,
+		wire.Bind(new(http.Handler), new(*in3.Router)),
+		web.NewServerConfig,
+		web.NewServer,
+		tracing.NewConfig,
+		tracing.NewProvider,
+		NewShutdownConfig,
 		NewManager,
 	)
 	return nil, nil
@@ -7,34 +7,93 @@
 package main
 
 import (
-	"fraud-scoring/internal/adapter/grpc/out"
+	in2 "fraud-scoring/internal/adapter/grpc/in"
+	out2 "fraud-scoring/internal/adapter/grpc/out"
+	"fraud-scoring/internal/adapter/history/out"
+	in3 "fraud-scoring/internal/adapter/http/in"
+	out4 "fraud-scoring/internal/adapter/idempotency/out"
 	"fraud-scoring/internal/adapter/kafka/in"
-	out2 "fraud-scoring/internal/adapter/kafka/out"
+	out3 "fraud-scoring/internal/adapter/kafka/out"
 	"fraud-scoring/internal/domain/application"
 	"fraud-scoring/internal/infra/grpc"
+	"fraud-scoring/internal/infra/health"
+	"fraud-scoring/internal/infra/http"
 	"fraud-scoring/internal/infra/kafka"
 	"fraud-scoring/internal/infra/logger"
+	"fraud-scoring/internal/infra/tracing"
 )
 
 // Injectors from wire.go:
 
 func buildAppContainer() (*Manager, error) {
+	historyConfig := out.NewHistoryConfig()
 	userTransactionsConfig := api.NewUserTransactionsConfig()
-	userTransactionsServiceClient := api.NewUserTransactionGrpc(userTransactionsConfig)
-	grpcUserTransactionsRepository := out.NewGrpcUserTransactionsRepository(userTransactionsServiceClient)
+	zapLogger := logger.NewLogger()
+	circuitBreakers := api.NewCircuitBreakers(userTransactionsConfig, zapLogger)
+	clientConn, err := api.NewUserTransactionsConn(userTransactionsConfig, circuitBreakers, zapLogger)
+	if err != nil {
+		return nil, err
+	}
+	userTransactionsServiceClient := api.NewUserTransactionGrpc(clientConn)
+	grpcUserTransactionsRepository := out2.NewGrpcUserTransactionsRepository(userTransactionsServiceClient)
+	cachedUserTransactions := out.NewCachedUserTransactions(historyConfig)
+	localUserTransactionsStore := out.NewLocalUserTransactionsStore(historyConfig)
+	defaultUserTransactions := out.NewDefaultUserTransactions(historyConfig)
+	chainUserTransactionsRepository, err := NewUserTransactionsChain(historyConfig, grpcUserTransactionsRepository, cachedUserTransactions, localUserTransactionsStore, defaultUserTransactions, zapLogger)
+	if err != nil {
+		return nil, err
+	}
 	saramaConfig := kafka.NewSaramaConfig()
-	cloudEventsSender, err := kafka.NewCloudEventsKafkaSender(saramaConfig)
+	producer, err := kafka.NewProducer(saramaConfig)
 	if err != nil {
 		return nil, err
 	}
-	zapLogger := logger.NewLogger()
-	kafkaTransactionScoreCard := out2.NewKafkaTransactionScoreCard(cloudEventsSender, zapLogger)
-	paymentRiskScoring := application.NewPaymentRiskScoring(grpcUserTransactionsRepository, kafkaTransactionScoreCard, zapLogger)
-	checkoutEventReceiver := in.NewCheckoutEventReceiver(paymentRiskScoring, zapLogger)
-	cloudEventsReceiver, err := kafka.NewCloudEventsKafkaConsumer(saramaConfig)
+	cloudEventsSender := kafka.NewCloudEventsKafkaSender(producer)
+	kafkaTransactionScoreCard := out3.NewKafkaTransactionScoreCard(cloudEventsSender, zapLogger)
+	recordingTransactionScoreCard := NewRecordingScoreCard(kafkaTransactionScoreCard, localUserTransactionsStore)
+	idempotencyConfig := out4.NewIdempotencyConfig()
+	inMemoryScoringResults := out4.NewInMemoryScoringResults(idempotencyConfig)
+	scoringConfig := NewScoringConfig()
+	paymentRiskScoring := application.NewPaymentRiskScoring(chainUserTransactionsRepository, recordingTransactionScoreCard, inMemoryScoringResults, scoringConfig, zapLogger)
+	transactionComponent := NewTransactionComponent()
+	kafkaTransactionRejections := out3.NewKafkaTransactionRejections(cloudEventsSender, zapLogger)
+	transactionValidation := application.NewTransactionValidation(transactionComponent, kafkaTransactionRejections, zapLogger)
+	eventRegistry, err := in.NewEventRegistry()
+	if err != nil {
+		return nil, err
+	}
+	checkoutTimeParser, err := NewCheckoutTimeParser()
+	if err != nil {
+		return nil, err
+	}
+	checkoutEventReceiver := in.NewCheckoutEventReceiver(paymentRiskScoring, transactionValidation, eventRegistry, checkoutTimeParser, zapLogger)
+	retrier := kafka.NewRetrier(saramaConfig, producer, zapLogger)
+	deadLetterQueue := kafka.NewDeadLetterQueue(saramaConfig, producer, zapLogger)
+	consumerGroup, err := NewConsumerGroup(saramaConfig, retrier, deadLetterQueue, circuitBreakers, producer, historyConfig, zapLogger)
+	if err != nil {
+		return nil, err
+	}
+	serverConfig := api.NewServerConfig()
+	grpcScoringService := in2.NewGrpcScoringService(paymentRiskScoring, checkoutTimeParser, zapLogger)
+	scoringServer := api.NewScoringServer(serverConfig, grpcScoringService, zapLogger)
+	webServerConfig := web.NewServerConfig()
+	scoreTransactionHandler := in3.NewScoreTransactionHandler(paymentRiskScoring, transactionComponent, zapLogger)
+	batchScoreHandler := in3.NewBatchScoreHandler(paymentRiskScoring, transactionComponent, zapLogger)
+	userTransactionsHandler, err := NewUserTransactionsHandler(historyConfig, grpcUserTransactionsRepository, cachedUserTransactions, localUserTransactionsStore, zapLogger)
+	if err != nil {
+		return nil, err
+	}
+	config := health.NewConfig()
+	checker := NewHealthChecker(config, consumerGroup, producer, clientConn)
+	healthHandler := in3.NewHealthHandler(checker, zapLogger)
+	router := in3.NewRouter(scoreTransactionHandler, batchScoreHandler, userTransactionsHandler, healthHandler)
+	server := web.NewServer(webServerConfig, router, zapLogger)
+	tracingConfig := tracing.NewConfig()
+	provider, err := tracing.NewProvider(tracingConfig, zapLogger)
 	if err != nil {
 		return nil, err
 	}
-	manager := NewManager(checkoutEventReceiver, cloudEventsReceiver)
+	shutdownConfig := NewShutdownConfig()
+	manager := NewManager(checkoutEventReceiver, consumerGroup, producer, clientConn, scoringServer, server, provider, shutdownConfig, zapLogger)
 	return manager, nil
 }
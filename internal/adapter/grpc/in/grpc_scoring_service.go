@@ -0,0 +1,195 @@
+package in
+
+import (
+	"context"
+	stderrors "errors"
+	"fraud-scoring/internal/domain"
+	"fraud-scoring/internal/domain/application"
+	"fraud-scoring/internal/domain/application/errors"
+	"fraud-scoring/internal/domain/history"
+	api "fraud-scoring/internal/infra/grpc"
+	"io"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var decisions = map[domain.Decision]api.Decision{
+	domain.DecisionApprove: api.Decision_DECISION_APPROVE,
+	domain.DecisionReview:  api.Decision_DECISION_REVIEW,
+	domain.DecisionDecline: api.Decision_DECISION_DECLINE,
+}
+
+// GrpcScoringService scores transactions synchronously for callers that can't wait on the Kafka round trip.
+type GrpcScoringService struct {
+	api.UnimplementedScoringServiceServer
+	scr   *application.PaymentRiskScoring
+	times *domain.CheckoutTimeParser
+	log   *zap.Logger
+}
+
+func (gss *GrpcScoringService) ScoreTransaction(ctx context.Context, req *api.ScoreTransactionRequest) (*api.ScoreTransactionResponse, error) {
+	result, err := gss.score(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return toResponse(result), nil
+}
+
+// ScoreTransactions answers every request in the order it was received, scoring up to SCORING_BATCH_CONCURRENCY
+// transactions at the same time with shared history lookups. A transaction that can't be scored gets a response
+// carrying its error instead of ending the stream.
+func (gss *GrpcScoringService) ScoreTransactions(stream api.ScoringService_ScoreTransactionsServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	items := make(chan application.BatchItem)
+	results := make(chan application.BatchResult)
+	recvErr := make(chan error, 1)
+	go func() {
+		defer close(items)
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF {
+					recvErr <- err
+				}
+				return
+			}
+			item := application.BatchItem{Key: req.GetPayment().GetId(), Vet: vet}
+			item.Transaction, item.Err = gss.toAnalysis(req)
+			select {
+			case items <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go gss.scr.AssessBatch(ctx, items, results)
+	for res := range results {
+		if err := stream.Send(gss.toStreamResponse(res)); err != nil {
+			return err
+		}
+	}
+	select {
+	case err := <-recvErr:
+		return err
+	default:
+		return ctx.Err()
+	}
+}
+
+func (gss *GrpcScoringService) toStreamResponse(res application.BatchResult) *api.ScoreTransactionResponse {
+	if res.Err == nil {
+		return toResponse(res.Result)
+	}
+	if _, ok := status.FromError(res.Err); !ok {
+		gss.log.Error("error to score transaction", zap.String("id", res.Key), zap.Error(res.Err))
+		res.Err = toStatus(res.Err)
+	}
+	st := status.Convert(res.Err)
+	return &api.ScoreTransactionResponse{
+		PaymentId: res.Key,
+		Error:     &api.ScoringError{Code: st.Code().String(), Message: st.Message()},
+	}
+}
+
+func (gss *GrpcScoringService) score(ctx context.Context, req *api.ScoreTransactionRequest) (*domain.ScoringResult, error) {
+	analysis, err := gss.toAnalysis(req)
+	if err != nil {
+		return nil, err
+	}
+	result, err := gss.scr.VettedAssessment(ctx, analysis, vet)
+	if err != nil {
+		gss.log.Error("error to score transaction", zap.String("id", analysis.Payment.Id), zap.Error(err))
+		return nil, toStatus(err)
+	}
+	return result, nil
+}
+
+// toAnalysis reads the transaction of req, failing with InvalidArgument when it can't be read.
+// A request has no time to fall back on, its checkout time is required.
+func (gss *GrpcScoringService) toAnalysis(req *api.ScoreTransactionRequest) (*domain.TransactionAnalysis, error) {
+	analysis, err := toCheckoutData(req).TransactionAnalysis(gss.times, time.Time{})
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid checkout time: %v", err)
+	}
+	return analysis, nil
+}
+
+// vet checks the transactions once they are known not to be duplicates, as the other entry points do, a transaction
+// that can't be scored fails with InvalidArgument.
+func vet(_ context.Context, analysis *domain.TransactionAnalysis) error {
+	if analysis.Participants.Buyer.Document == "" {
+		return errors.TransactionRejected{Errors: []string{"buyer document is required"}}
+	}
+	return nil
+}
+
+func toStatus(err error) error {
+	var budget errors.ScoringBudgetExceeded
+	var lastOrder errors.LastOrderNotFound
+	var average errors.AverageTransactionsNotFound
+	var rejected errors.TransactionRejected
+	switch {
+	case stderrors.As(err, &rejected):
+		return status.Error(codes.InvalidArgument, strings.Join(rejected.Errors, ", "))
+	case stderrors.Is(err, errors.ErrIdempotencyKeyReused):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case stderrors.Is(err, errors.ErrScoringInProgress):
+		return status.Error(codes.Aborted, err.Error())
+	case stderrors.As(err, &budget):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case stderrors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case stderrors.As(err, &lastOrder), stderrors.As(err, &average):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func toCheckoutData(req *api.ScoreTransactionRequest) *domain.CheckoutData {
+	data := &domain.CheckoutData{}
+	data.Checkout.Id = req.GetCheckout().GetId()
+	data.Checkout.BuyerInfo.Document = req.GetCheckout().GetBuyerInfo().GetDocument()
+	data.Checkout.BuyerInfo.Name = req.GetCheckout().GetBuyerInfo().GetName()
+	data.Checkout.CardInfo.CardInfo = req.GetCheckout().GetCardInfo().GetCardInfo()
+	data.Checkout.CardInfo.Token = req.GetCheckout().GetCardInfo().GetToken()
+	data.Checkout.IdempotencyKey = req.GetCheckout().GetIdempotencyKey()
+	data.Checkout.At = req.GetCheckout().GetAt()
+	data.Payment.Id = req.GetPayment().GetId()
+	data.Payment.Amount = req.GetPayment().GetAmount()
+	data.Payment.Currency = req.GetPayment().GetCurrency()
+	data.Payment.Status = req.GetPayment().GetStatus()
+	data.Payment.SellerInfo.SellerId = req.GetPayment().GetSellerInfo().GetSellerId()
+	data.Payment.IdempotencyKey = req.GetPayment().GetIdempotencyKey()
+	return data
+}
+
+func toResponse(result *domain.ScoringResult) *api.ScoreTransactionResponse {
+	return &api.ScoreTransactionResponse{
+		PaymentId: result.Transaction.Payment.Id,
+		Score: &api.ScoreCard{
+			ValueScore:        int32(result.Score.ValueScore.Score),
+			SellerScore:       int32(result.Score.SellerScore.Score),
+			AverageValueScore: int32(result.Score.AverageValueScore.Score),
+			CurrencyScore:     int32(result.Score.CurrencyScore.Score),
+		},
+		Decision: decisions[result.Decision],
+		Provenance: &api.ScoreProvenance{
+			LastOrder:       toProvenance(result.Provenance.LastOrder),
+			AverageBaseline: toProvenance(result.Provenance.AverageBaseline),
+		},
+	}
+}
+
+func toProvenance(p history.Provenance) *api.HistoryProvenance {
+	return &api.HistoryProvenance{Source: p.Source, AsOf: p.AsOf.Format(time.RFC3339)}
+}
+
+func NewGrpcScoringService(scr *application.PaymentRiskScoring, times *domain.CheckoutTimeParser, log *zap.Logger) *GrpcScoringService {
+	return &GrpcScoringService{scr: scr, times: times, log: log}
+}
@@ -0,0 +1,203 @@
+package in
+
+import (
+	"context"
+	"errors"
+	"fraud-scoring/internal/domain"
+	"fraud-scoring/internal/domain/application"
+	"fraud-scoring/internal/domain/history"
+	api "fraud-scoring/internal/infra/grpc"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+type stubHistory struct {
+	err error
+}
+
+func (s *stubHistory) LastOrder(context.Context, string) (*history.LastOrder, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &history.LastOrder{
+		SellerId:   "seller-123",
+		Currency:   "BRL",
+		Amount:     "100.00",
+		Provenance: history.Provenance{Source: history.SourceGrpc, AsOf: time.Now()},
+	}, nil
+}
+
+func (s *stubHistory) AverageTransactions(context.Context, string, time.Time) (*history.AveragePayment, error) {
+	return nil, errors.New("not used")
+}
+
+func (s *stubHistory) AverageBaseline(context.Context, string, time.Time, int) (*history.AverageBaseline, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &history.AverageBaseline{Amount: "100.00", Provenance: history.Provenance{Source: history.SourceCache}}, nil
+}
+
+type stubScoreCard struct{}
+
+func (stubScoreCard) Store(context.Context, *domain.ScoringResult) error { return nil }
+
+func newScoringClient(t *testing.T, utr *stubHistory) (api.ScoringServiceClient, *grpc.ClientConn) {
+	log := zaptest.NewLogger(t)
+	config := &application.ScoringConfig{
+		Budget:         time.Second,
+		BaselineMonths: 3,
+		Decision:       domain.DecisionPolicy{ReviewAt: -3, DeclineAt: -6},
+	}
+	scr := application.NewPaymentRiskScoring(utr, stubScoreCard{}, nil, config, log)
+	times := domain.NewCheckoutTimeParser([]string{"2006-01-02T15:04:05"}, time.UTC)
+	server := api.NewScoringServer(api.NewServerConfig(), NewGrpcScoringService(scr, times, log), log)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go server.ServeListener(lis)
+	t.Cleanup(server.GracefulStop)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return api.NewScoringServiceClient(conn), conn
+}
+
+func createScoreRequest(paymentId, at string) *api.ScoreTransactionRequest {
+	return &api.ScoreTransactionRequest{
+		Checkout: &api.Checkout{
+			Id:        "checkout-123",
+			BuyerInfo: &api.BuyerInfo{Document: "12345678901", Name: "John Doe"},
+			CardInfo:  &api.CardInfo{CardInfo: "****1234", Token: "tok_123"},
+			At:        at,
+		},
+		Payment: &api.Payment{
+			Id:         paymentId,
+			Amount:     "100.00",
+			Currency:   "BRL",
+			Status:     "completed",
+			SellerInfo: &api.SellerInfo{SellerId: "seller-123"},
+		},
+	}
+}
+
+func TestGrpcScoringService_ScoreTransaction(t *testing.T) {
+	client, _ := newScoringClient(t, &stubHistory{})
+
+	res, err := client.ScoreTransaction(context.Background(), createScoreRequest("payment-1", "2024-01-15T10:30:00.000000"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if res.PaymentId != "payment-1" || res.Decision == api.Decision_DECISION_UNSPECIFIED || res.Score == nil {
+		t.Errorf("Unexpected response %+v", res)
+	}
+	if res.Provenance.GetAverageBaseline().GetSource() != history.SourceCache {
+		t.Errorf("Expected baseline provenance, got %+v", res.Provenance)
+	}
+}
+
+func TestGrpcScoringService_ScoreTransaction_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		history      *stubHistory
+		at           string
+		expectedCode codes.Code
+	}{
+		{
+			name:         "Malformed checkout time",
+			history:      &stubHistory{},
+			at:           "15/01/2024",
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "History unavailable",
+			history:      &stubHistory{err: errors.New("connection refused")},
+			at:           "2024-01-15T10:30:00.000000",
+			expectedCode: codes.Unavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newScoringClient(t, tt.history)
+			_, err := client.ScoreTransaction(context.Background(), createScoreRequest("payment-1", tt.at))
+			if status.Code(err) != tt.expectedCode {
+				t.Errorf("Expected %s, got %v", tt.expectedCode, err)
+			}
+		})
+	}
+}
+
+func TestGrpcScoringService_ScoreTransactions_KeepsOrderAndReportsErrors(t *testing.T) {
+	client, _ := newScoringClient(t, &stubHistory{})
+	stream, err := client.ScoreTransactions(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+
+	requests := []*api.ScoreTransactionRequest{
+		createScoreRequest("payment-1", "2024-01-15T10:30:00.000000"),
+		createScoreRequest("payment-2", "not a time"),
+		createScoreRequest("payment-3", "2024-01-15T10:31:00.000000"),
+	}
+	for _, req := range requests {
+		if err := stream.Send(req); err != nil {
+			t.Fatalf("Failed to send: %v", err)
+		}
+	}
+	_ = stream.CloseSend()
+
+	var responses []*api.ScoreTransactionResponse
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Expected the stream to survive a bad transaction, got %v", err)
+		}
+		responses = append(responses, res)
+	}
+
+	if len(responses) != len(requests) {
+		t.Fatalf("Expected %d responses, got %d", len(requests), len(responses))
+	}
+	for i, res := range responses {
+		if res.PaymentId != requests[i].Payment.Id {
+			t.Errorf("Expected response %d for %s, got %s", i, requests[i].Payment.Id, res.PaymentId)
+		}
+	}
+	if responses[1].Error.GetCode() != codes.InvalidArgument.String() || responses[1].Score != nil {
+		t.Errorf("Expected invalid argument error for payment-2, got %+v", responses[1])
+	}
+	if responses[0].Error != nil || responses[2].Error != nil {
+		t.Error("Expected valid transactions to be scored")
+	}
+}
+
+func TestScoringServer_ReportsHealth(t *testing.T) {
+	_, conn := newScoringClient(t, &stubHistory{})
+
+	res, err := healthpb.NewHealthClient(conn).Check(context.Background(),
+		&healthpb.HealthCheckRequest{Service: api.ScoringService_ServiceDesc.ServiceName})
+	if err != nil {
+		t.Fatalf("Expected health check to succeed, got %v", err)
+	}
+	if res.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Expected SERVING, got %s", res.Status)
+	}
+}
@@ -2,20 +2,34 @@ package out
 
 import (
 	"context"
+	"errors"
 	"fraud-scoring/internal/domain/history"
+	"fraud-scoring/internal/domain/repositories"
 	api "fraud-scoring/internal/infra/grpc"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+var tracer = otel.Tracer("fraud-scoring/internal/adapter/grpc/out")
+
 type GrpcUserTransactionsRepository struct {
 	grpc api.UserTransactionsServiceClient
 }
 
-func (gutr *GrpcUserTransactionsRepository) LastOrder(document string) (*history.LastOrder, error) {
+func (gutr *GrpcUserTransactionsRepository) LastOrder(ctx context.Context, document string) (lo *history.LastOrder, err error) {
+	ctx, span := tracer.Start(ctx, "GrpcUserTransactionsRepository.LastOrder")
+	defer func() { endSpan(span, err) }()
 	arg := &api.LastUserTransactionRequest{Document: document}
-	res, err := gutr.grpc.GetLastUserTransaction(context.Background(), arg)
+	res, err := gutr.grpc.GetLastUserTransaction(ctx, arg)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err)
 	}
 	return &history.LastOrder{
 		SellerId: res.SellerId,
@@ -24,14 +38,17 @@ func (gutr *GrpcUserTransactionsRepository) LastOrder(document string) (*history
 	}, nil
 }
 
-func (gutr *GrpcUserTransactionsRepository) AverageTransactions(document string, at time.Time) (*history.AveragePayment, error) {
+func (gutr *GrpcUserTransactionsRepository) AverageTransactions(ctx context.Context, document string, at time.Time) (ap *history.AveragePayment, err error) {
+	ctx, span := tracer.Start(ctx, "GrpcUserTransactionsRepository.AverageTransactions",
+		trace.WithAttributes(attribute.String("history.month", at.Format(history.MonthLayout))))
+	defer func() { endSpan(span, err) }()
 	arg := &api.UserMonthAverageRequest{
 		Document: document,
-		Month:    at.String(),
+		Month:    at.Format(history.MonthLayout),
 	}
-	res, err := gutr.grpc.GetUserMonthAverage(context.Background(), arg)
+	res, err := gutr.grpc.GetUserMonthAverage(ctx, arg)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err)
 	}
 	return &history.AveragePayment{
 		Month:  res.Month,
@@ -39,6 +56,64 @@ func (gutr *GrpcUserTransactionsRepository) AverageTransactions(document string,
 	}, nil
 }
 
+func (gutr *GrpcUserTransactionsRepository) AverageBaseline(ctx context.Context, document string, at time.Time, months int) (ab *history.AverageBaseline, err error) {
+	ctx, span := tracer.Start(ctx, "GrpcUserTransactionsRepository.AverageBaseline",
+		trace.WithAttributes(attribute.Int("history.months", months)))
+	defer func() { endSpan(span, err) }()
+	samples := make([]*history.AveragePayment, months)
+	g, gctx := errgroup.WithContext(ctx)
+	for i := range samples {
+		i := i
+		month := time.Date(at.Year(), at.Month()-time.Month(i+1), 1, 0, 0, 0, 0, at.Location())
+		g.Go(func() error {
+			avg, err := gutr.AverageTransactions(gctx, document, month)
+			if errors.As(err, &repositories.HistoryNotFound{}) {
+				return nil
+			}
+			samples[i] = avg
+			return err
+		})
+	}
+	if err = g.Wait(); err != nil {
+		return nil, err
+	}
+	baseline, err := history.NewAverageBaseline(samples)
+	if errors.Is(err, history.ErrNoMonthlyAverages) {
+		return gutr.currentMonthBaseline(ctx, document, at)
+	}
+	return baseline, err
+}
+
+// currentMonthBaseline is the baseline of a buyer without transactions in the months before at: the average of the
+// current month so far, or an empty baseline when the buyer has no transactions at all.
+func (gutr *GrpcUserTransactionsRepository) currentMonthBaseline(ctx context.Context, document string, at time.Time) (*history.AverageBaseline, error) {
+	avg, err := gutr.AverageTransactions(ctx, document, at)
+	if errors.As(err, &repositories.HistoryNotFound{}) {
+		return &history.AverageBaseline{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return history.NewAverageBaseline([]*history.AveragePayment{avg})
+}
+
+// endSpan ends span, marking it as failed unless err is nil or the buyer has no history.
+func endSpan(span trace.Span, err error) {
+	if err != nil && !errors.As(err, &repositories.HistoryNotFound{}) {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+	span.End()
+}
+
+// notFound reports a NotFound status as the buyer having no history.
+func notFound(err error) error {
+	if status.Code(err) == codes.NotFound {
+		return repositories.HistoryNotFound{Err: err}
+	}
+	return err
+}
+
 func NewGrpcUserTransactionsRepository(grpc api.UserTransactionsServiceClient) *GrpcUserTransactionsRepository {
 	return &GrpcUserTransactionsRepository{grpc: grpc}
 }
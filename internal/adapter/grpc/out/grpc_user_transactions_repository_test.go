@@ -0,0 +1,157 @@
+package out
+
+import (
+	"context"
+	"errors"
+	"fraud-scoring/internal/domain/repositories"
+	"net"
+	"testing"
+	"time"
+
+	api "fraud-scoring/internal/infra/grpc"
+	"fraud-scoring/internal/infra/grpc/fake"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newComponentRepository serves seed from a fake backend and connects a repository to it through the production client.
+func newComponentRepository(t *testing.T, seed *fake.Seed) *GrpcUserTransactionsRepository {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	srv := grpc.NewServer()
+	api.RegisterUserTransactionsServiceServer(srv, fake.NewServer(seed))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	config := api.NewUserTransactionsConfig()
+	config.Host = lis.Addr().String()
+	config.ConnectTimeout = 5 * time.Second
+	config.Retry.InitialBackoff = 10 * time.Millisecond
+	config.Retry.MaxBackoff = 10 * time.Millisecond
+	log := zaptest.NewLogger(t)
+	conn, err := api.NewUserTransactionsConn(config, api.NewCircuitBreakers(config, log), log)
+	if err != nil {
+		t.Fatalf("Failed to connect to fake backend: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewGrpcUserTransactionsRepository(api.NewUserTransactionGrpc(conn))
+}
+
+func TestGrpcUserTransactionsRepository_LastOrder(t *testing.T) {
+	repo := newComponentRepository(t, &fake.Seed{Users: map[string]*fake.User{
+		"12345678901": {LastTransaction: &fake.Transaction{SellerId: "seller-123", Currency: "BRL", Value: "100.00"}},
+	}})
+
+	lo, err := repo.LastOrder(context.Background(), "12345678901")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if lo.SellerId != "seller-123" || lo.Currency != "BRL" || lo.Amount != "100.00" {
+		t.Errorf("Unexpected last order %+v", lo)
+	}
+
+	_, err = repo.LastOrder(context.Background(), "00000000000")
+	if !errors.As(err, &repositories.HistoryNotFound{}) || status.Code(err) != codes.NotFound {
+		t.Errorf("Expected HistoryNotFound for an unknown document, got %v", err)
+	}
+}
+
+func TestGrpcUserTransactionsRepository_AverageTransactions_SendsMonth(t *testing.T) {
+	repo := newComponentRepository(t, &fake.Seed{Users: map[string]*fake.User{
+		"12345678901": {MonthlyAverages: map[string]string{"2024-01": "120.00"}},
+	}})
+
+	at := time.Date(2024, time.January, 31, 23, 59, 0, 0, time.UTC)
+	avg, err := repo.AverageTransactions(context.Background(), "12345678901", at)
+	if err != nil {
+		t.Fatalf("Expected the month to be sent as YYYY-MM, got %v", err)
+	}
+	if avg.Month != "2024-01" || avg.Amount != "120.00" {
+		t.Errorf("Unexpected average %+v", avg)
+	}
+}
+
+func TestGrpcUserTransactionsRepository_AverageBaseline(t *testing.T) {
+	repo := newComponentRepository(t, &fake.Seed{Users: map[string]*fake.User{
+		"12345678901": {MonthlyAverages: map[string]string{
+			"2024-03": "999.00", // current month, excluded from the baseline
+			"2024-02": "300.00",
+			"2023-12": "0",
+		}},
+	}})
+
+	at := time.Date(2024, time.March, 15, 10, 0, 0, 0, time.UTC)
+	baseline, err := repo.AverageBaseline(context.Background(), "12345678901", at, 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// (300*3 + 0*1) / 4, January has no transactions
+	if baseline.Amount != "225.00" {
+		t.Errorf("Expected baseline 225.00, got %s", baseline.Amount)
+	}
+	if len(baseline.Months) != 2 || baseline.Months[0].Month != "2024-02" {
+		t.Errorf("Unexpected baseline months %+v", baseline.Months)
+	}
+}
+
+func TestGrpcUserTransactionsRepository_AverageBaseline_NoPriorHistory(t *testing.T) {
+	repo := newComponentRepository(t, &fake.Seed{Users: map[string]*fake.User{
+		"12345678901": {MonthlyAverages: map[string]string{"2024-03": "80.00"}},
+	}})
+	at := time.Date(2024, time.March, 15, 10, 0, 0, 0, time.UTC)
+
+	// Only the current month has transactions, it stands in for the baseline
+	baseline, err := repo.AverageBaseline(context.Background(), "12345678901", at, 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if baseline.Amount != "80.00" {
+		t.Errorf("Expected the current month average, got %s", baseline.Amount)
+	}
+
+	// A buyer without any transaction gets an empty baseline, not an error
+	baseline, err = repo.AverageBaseline(context.Background(), "00000000000", at, 3)
+	if err != nil {
+		t.Fatalf("Expected no error for a buyer without history, got %v", err)
+	}
+	if !baseline.Empty() {
+		t.Errorf("Expected an empty baseline, got %+v", baseline)
+	}
+}
+
+func TestGrpcUserTransactionsRepository_RetriesInjectedFailures(t *testing.T) {
+	repo := newComponentRepository(t, &fake.Seed{Users: map[string]*fake.User{
+		"12345678901": {
+			LastTransaction: &fake.Transaction{SellerId: "seller-123", Currency: "BRL", Value: "100.00"},
+			Faults:          []*fake.Fault{{Code: codes.Unavailable, Message: "replica restarting", Times: 2}},
+		},
+	}})
+
+	if _, err := repo.LastOrder(context.Background(), "12345678901"); err != nil {
+		t.Errorf("Expected transient failures to be retried, got %v", err)
+	}
+}
+
+func TestGrpcUserTransactionsRepository_InjectedLatencyHonoursDeadline(t *testing.T) {
+	repo := newComponentRepository(t, &fake.Seed{Users: map[string]*fake.User{
+		"12345678901": {
+			LastTransaction: &fake.Transaction{SellerId: "seller-123"},
+			Faults:          []*fake.Fault{{Latency: fake.Duration(time.Second)}},
+		},
+	}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := repo.LastOrder(ctx, "12345678901")
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("Expected the call to give up at the deadline, took %s", time.Since(start))
+	}
+}
@@ -0,0 +1,94 @@
+package out
+
+import (
+	"context"
+	"errors"
+	"fraud-scoring/internal/domain/history"
+	"fraud-scoring/internal/infra/lru"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var ErrNotCached = errors.New("no fresh cached history for buyer")
+
+// CachedUserTransactions serves the answers previously given by the providers ahead of it in the chain, for as long as
+// they are younger than the configured TTL.
+type CachedUserTransactions struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	now       func() time.Time
+	lastOrder *lru.Cache[string, history.LastOrder]
+	averages  *lru.Cache[string, history.AveragePayment]
+	baselines *lru.Cache[string, history.AverageBaseline]
+}
+
+func (cut *CachedUserTransactions) LastOrder(_ context.Context, document string) (*history.LastOrder, error) {
+	cut.mu.Lock()
+	defer cut.mu.Unlock()
+	lo, ok := cut.lastOrder.Get(document)
+	if !ok || !cut.fresh(lo.Provenance) {
+		return nil, ErrNotCached
+	}
+	return &lo, nil
+}
+
+func (cut *CachedUserTransactions) AverageTransactions(_ context.Context, document string, at time.Time) (*history.AveragePayment, error) {
+	cut.mu.Lock()
+	defer cut.mu.Unlock()
+	avg, ok := cut.averages.Get(averageKey(document, at))
+	if !ok || !cut.fresh(avg.Provenance) {
+		return nil, ErrNotCached
+	}
+	return &avg, nil
+}
+
+func (cut *CachedUserTransactions) AverageBaseline(_ context.Context, document string, at time.Time, months int) (*history.AverageBaseline, error) {
+	cut.mu.Lock()
+	defer cut.mu.Unlock()
+	baseline, ok := cut.baselines.Get(baselineKey(document, at, months))
+	if !ok || !cut.fresh(baseline.Provenance) {
+		return nil, ErrNotCached
+	}
+	return &baseline, nil
+}
+
+func (cut *CachedUserTransactions) fresh(p history.Provenance) bool {
+	return cut.now().Sub(p.AsOf) <= cut.ttl
+}
+
+func (cut *CachedUserTransactions) recordLastOrder(document string, lo *history.LastOrder) {
+	cut.mu.Lock()
+	defer cut.mu.Unlock()
+	cut.lastOrder.Put(document, *lo)
+}
+
+func (cut *CachedUserTransactions) recordAverage(document string, at time.Time, avg *history.AveragePayment) {
+	cut.mu.Lock()
+	defer cut.mu.Unlock()
+	cut.averages.Put(averageKey(document, at), *avg)
+}
+
+func (cut *CachedUserTransactions) recordBaseline(document string, at time.Time, months int, baseline *history.AverageBaseline) {
+	cut.mu.Lock()
+	defer cut.mu.Unlock()
+	cut.baselines.Put(baselineKey(document, at, months), *baseline)
+}
+
+func averageKey(document string, at time.Time) string {
+	return document + "|" + at.Format(history.MonthLayout)
+}
+
+func baselineKey(document string, at time.Time, months int) string {
+	return averageKey(document, at) + "|" + strconv.Itoa(months)
+}
+
+func NewCachedUserTransactions(config *HistoryConfig) *CachedUserTransactions {
+	return &CachedUserTransactions{
+		ttl:       config.CacheTTL,
+		now:       time.Now,
+		lastOrder: lru.New[string, history.LastOrder](config.CacheSize),
+		averages:  lru.New[string, history.AveragePayment](config.CacheSize),
+		baselines: lru.New[string, history.AverageBaseline](config.CacheSize),
+	}
+}
@@ -0,0 +1,145 @@
+package out
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fraud-scoring/internal/domain/history"
+	"fraud-scoring/internal/domain/repositories"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var historyAnswers = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "fraud_scoring_history_answers_total",
+	Help: "History lookups per lookup and provider that answered, none when every provider failed.",
+}, []string{"lookup", "source"})
+
+type historyProvider struct {
+	name string
+	repo repositories.UserTransactionsRepository
+}
+
+// historyRecorder is implemented by providers that keep the answers given by the providers ahead of them.
+type historyRecorder interface {
+	recordLastOrder(document string, lo *history.LastOrder)
+	recordAverage(document string, at time.Time, avg *history.AveragePayment)
+	recordBaseline(document string, at time.Time, months int, baseline *history.AverageBaseline)
+}
+
+// ChainUserTransactionsRepository asks its providers in order and returns the first answer, stamped with the name of the
+// provider that served it. Answers are recorded by the providers further down the chain that keep them.
+type ChainUserTransactionsRepository struct {
+	providers []historyProvider
+	now       func() time.Time
+	log       *zap.Logger
+}
+
+func (cutr *ChainUserTransactionsRepository) LastOrder(ctx context.Context, document string) (*history.LastOrder, error) {
+	return serve(cutr, "last_order", document,
+		func(repo repositories.UserTransactionsRepository) (*history.LastOrder, error) {
+			return repo.LastOrder(ctx, document)
+		},
+		func(lo *history.LastOrder) *history.Provenance { return &lo.Provenance },
+		func(r historyRecorder, lo *history.LastOrder) { r.recordLastOrder(document, lo) },
+	)
+}
+
+func (cutr *ChainUserTransactionsRepository) AverageTransactions(ctx context.Context, document string, at time.Time) (*history.AveragePayment, error) {
+	return serve(cutr, "average_transactions", document,
+		func(repo repositories.UserTransactionsRepository) (*history.AveragePayment, error) {
+			return repo.AverageTransactions(ctx, document, at)
+		},
+		func(avg *history.AveragePayment) *history.Provenance { return &avg.Provenance },
+		func(r historyRecorder, avg *history.AveragePayment) { r.recordAverage(document, at, avg) },
+	)
+}
+
+func (cutr *ChainUserTransactionsRepository) AverageBaseline(ctx context.Context, document string, at time.Time, months int) (*history.AverageBaseline, error) {
+	return serve(cutr, "average_baseline", document,
+		func(repo repositories.UserTransactionsRepository) (*history.AverageBaseline, error) {
+			return repo.AverageBaseline(ctx, document, at, months)
+		},
+		func(baseline *history.AverageBaseline) *history.Provenance { return &baseline.Provenance },
+		func(r historyRecorder, baseline *history.AverageBaseline) {
+			r.recordBaseline(document, at, months, baseline)
+		},
+	)
+}
+
+// serve runs lookup against every provider until one answers. A provider telling that the buyer has no history answers
+// too, the providers after it only stand in for the ones that are unavailable. When all of them fail the error of the
+// first provider is returned, as it is the most authoritative one.
+func serve[T any](
+	cutr *ChainUserTransactionsRepository,
+	lookup, document string,
+	fetch func(repositories.UserTransactionsRepository) (*T, error),
+	provenance func(*T) *history.Provenance,
+	record func(historyRecorder, *T),
+) (*T, error) {
+	var first error
+	for i, provider := range cutr.providers {
+		answer, err := fetch(provider.repo)
+		if errors.As(err, &repositories.HistoryNotFound{}) {
+			historyAnswers.WithLabelValues(lookup, provider.name).Inc()
+			return nil, err
+		}
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			cutr.log.Warn("history provider failed, falling back",
+				zap.String("lookup", lookup),
+				zap.String("provider", provider.name),
+				zap.String("document", document),
+				zap.Error(err))
+			continue
+		}
+		p := provenance(answer)
+		p.Source = provider.name
+		if p.AsOf.IsZero() {
+			p.AsOf = cutr.now()
+		}
+		for _, next := range cutr.providers[i+1:] {
+			if r, ok := next.repo.(historyRecorder); ok {
+				record(r, answer)
+			}
+		}
+		historyAnswers.WithLabelValues(lookup, provider.name).Inc()
+		return answer, nil
+	}
+	historyAnswers.WithLabelValues(lookup, "none").Inc()
+	return nil, first
+}
+
+// NewChainUserTransactionsRepository orders remote and the local providers as listed in config.Providers.
+func NewChainUserTransactionsRepository(
+	config *HistoryConfig,
+	remote repositories.UserTransactionsRepository,
+	cache *CachedUserTransactions,
+	local *LocalUserTransactionsStore,
+	defaults *DefaultUserTransactions,
+	log *zap.Logger,
+) (*ChainUserTransactionsRepository, error) {
+	available := map[string]repositories.UserTransactionsRepository{
+		history.SourceGrpc:    remote,
+		history.SourceCache:   cache,
+		history.SourceLocal:   local,
+		history.SourceDefault: defaults,
+	}
+	chain := &ChainUserTransactionsRepository{now: time.Now, log: log}
+	for _, name := range config.Providers {
+		repo, ok := available[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown history provider %q", name)
+		}
+		chain.providers = append(chain.providers, historyProvider{name: name, repo: repo})
+	}
+	if len(chain.providers) == 0 {
+		return nil, fmt.Errorf("no history providers configured")
+	}
+	return chain, nil
+}
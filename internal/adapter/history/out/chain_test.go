@@ -0,0 +1,142 @@
+package out
+
+import (
+	"context"
+	"errors"
+	"fraud-scoring/internal/domain/history"
+	"fraud-scoring/internal/domain/repositories"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+type stubRemote struct {
+	err       error
+	lastOrder *history.LastOrder
+	baseline  *history.AverageBaseline
+}
+
+func (s *stubRemote) LastOrder(context.Context, string) (*history.LastOrder, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	lo := *s.lastOrder
+	return &lo, nil
+}
+
+func (s *stubRemote) AverageTransactions(_ context.Context, _ string, at time.Time) (*history.AveragePayment, error) {
+	return nil, s.err
+}
+
+func (s *stubRemote) AverageBaseline(context.Context, string, time.Time, int) (*history.AverageBaseline, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	b := *s.baseline
+	return &b, nil
+}
+
+func newTestChain(t *testing.T, config *HistoryConfig, remote *stubRemote) (*ChainUserTransactionsRepository, *CachedUserTransactions, *LocalUserTransactionsStore) {
+	cache := NewCachedUserTransactions(config)
+	local := NewLocalUserTransactionsStore(config)
+	chain, err := NewChainUserTransactionsRepository(config, remote, cache, local, NewDefaultUserTransactions(config), zaptest.NewLogger(t))
+	if err != nil {
+		t.Fatalf("Expected chain to build, got %v", err)
+	}
+	return chain, cache, local
+}
+
+func testHistoryConfig() *HistoryConfig {
+	config := NewHistoryConfig()
+	config.Defaults = DefaultsConfig{Currency: "BRL", BaselineAmount: "50.00"}
+	return config
+}
+
+func TestChainUserTransactionsRepository_FallsBackInOrder(t *testing.T) {
+	remote := &stubRemote{
+		lastOrder: &history.LastOrder{SellerId: "seller-123", Currency: "USD", Amount: "100.00"},
+		baseline:  &history.AverageBaseline{Amount: "80.00"},
+	}
+	chain, cache, local := newTestChain(t, testHistoryConfig(), remote)
+	ctx := context.Background()
+	at := time.Date(2024, time.March, 15, 10, 0, 0, 0, time.UTC)
+
+	lo, err := chain.LastOrder(ctx, "12345678901")
+	if err != nil || lo.Provenance.Source != history.SourceGrpc || lo.Provenance.AsOf.IsZero() {
+		t.Fatalf("Expected remote answer stamped with its provenance, got %+v, %v", lo, err)
+	}
+	fetchedAt := lo.Provenance.AsOf
+	if _, err := chain.AverageBaseline(ctx, "12345678901", at, 3); err != nil {
+		t.Fatalf("Expected remote baseline, got %v", err)
+	}
+
+	remote.err = errors.New("unavailable")
+	lo, err = chain.LastOrder(ctx, "12345678901")
+	if err != nil || lo.Provenance.Source != history.SourceCache || !lo.Provenance.AsOf.Equal(fetchedAt) || lo.SellerId != "seller-123" {
+		t.Errorf("Expected cached answer keeping its original fetch time, got %+v, %v", lo, err)
+	}
+	baseline, err := chain.AverageBaseline(ctx, "12345678901", at, 3)
+	if err != nil || baseline.Provenance.Source != history.SourceCache || baseline.Amount != "80.00" {
+		t.Errorf("Expected cached baseline, got %+v, %v", baseline, err)
+	}
+
+	cache.now = func() time.Time { return time.Now().Add(48 * time.Hour) }
+	lo, err = chain.LastOrder(ctx, "12345678901")
+	if err != nil || lo.Provenance.Source != history.SourceDefault || lo.Currency != "BRL" {
+		t.Errorf("Expected defaults once the cache is stale, got %+v, %v", lo, err)
+	}
+
+	local.Record(newScoredOrder("12345678901", "seller-456", "30.00", at))
+	lo, err = chain.LastOrder(ctx, "12345678901")
+	if err != nil || lo.Provenance.Source != history.SourceLocal || lo.SellerId != "seller-456" || !lo.Provenance.AsOf.Equal(at) {
+		t.Errorf("Expected locally recorded answer, got %+v, %v", lo, err)
+	}
+}
+
+func TestChainUserTransactionsRepository_ReturnsFirstErrorWhenAllFail(t *testing.T) {
+	config := testHistoryConfig()
+	config.Providers = []string{history.SourceGrpc, history.SourceCache}
+	remoteErr := errors.New("unavailable")
+	chain, _, _ := newTestChain(t, config, &stubRemote{err: remoteErr})
+
+	if _, err := chain.LastOrder(context.Background(), "12345678901"); !errors.Is(err, remoteErr) {
+		t.Errorf("Expected the remote error, got %v", err)
+	}
+}
+
+func TestChainUserTransactionsRepository_StopsWhenBuyerHasNoHistory(t *testing.T) {
+	notFound := repositories.HistoryNotFound{Err: errors.New("user not found")}
+	chain, _, _ := newTestChain(t, testHistoryConfig(), &stubRemote{err: notFound})
+
+	if lo, err := chain.LastOrder(context.Background(), "12345678901"); !errors.As(err, &repositories.HistoryNotFound{}) {
+		t.Errorf("Expected the buyer to have no history instead of the defaults, got %+v, %v", lo, err)
+	}
+}
+
+func TestNewChainUserTransactionsRepository_UnknownProvider(t *testing.T) {
+	config := testHistoryConfig()
+	config.Providers = []string{history.SourceGrpc, "redis"}
+	_, err := NewChainUserTransactionsRepository(config, &stubRemote{}, nil, nil, nil, zaptest.NewLogger(t))
+	if err == nil {
+		t.Error("Expected error for an unknown provider")
+	}
+}
+
+func TestHistoryConfig_Needs(t *testing.T) {
+	tests := []struct {
+		providers []string
+		needs     bool
+	}{
+		{providers: []string{history.SourceGrpc, history.SourceCache, history.SourceLocal, history.SourceDefault}},
+		{providers: []string{history.SourceGrpc}, needs: true},
+		{providers: []string{history.SourceCache, history.SourceGrpc}, needs: true},
+		{providers: []string{history.SourceLocal}},
+	}
+	for _, tt := range tests {
+		config := &HistoryConfig{Providers: tt.providers}
+		if needs := config.Needs(history.SourceGrpc); needs != tt.needs {
+			t.Errorf("Expected Needs(grpc) = %v with providers %v, got %v", tt.needs, tt.providers, needs)
+		}
+	}
+}
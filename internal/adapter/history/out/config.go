@@ -0,0 +1,45 @@
+package out
+
+import (
+	"fraud-scoring/internal/domain/history"
+	"fraud-scoring/internal/infra/env"
+	"time"
+)
+
+type HistoryConfig struct {
+	// Providers names the history providers asked for each lookup, in order, until one answers.
+	Providers []string
+	// CacheTTL is how long an answer of the remote service can be served again while it is unavailable.
+	CacheTTL time.Duration
+	// CacheSize and LocalStoreSize bound the number of buyers kept in memory.
+	CacheSize      int
+	LocalStoreSize int
+	Defaults       DefaultsConfig
+}
+
+// DefaultsConfig is the history assumed while no other provider answers, none when empty.
+type DefaultsConfig struct {
+	Currency       string
+	BaselineAmount string
+}
+
+// Needs reports whether the lookups can't be answered while source is down: it is the last of the providers, none is
+// asked after it.
+func (hc *HistoryConfig) Needs(source string) bool {
+	return len(hc.Providers) > 0 && hc.Providers[len(hc.Providers)-1] == source
+}
+
+func NewHistoryConfig() *HistoryConfig {
+	return &HistoryConfig{
+		Providers: env.Strings("HISTORY_PROVIDERS", []string{
+			history.SourceGrpc, history.SourceCache, history.SourceLocal, history.SourceDefault,
+		}),
+		CacheTTL:       env.Duration("HISTORY_CACHE_TTL", 24*time.Hour),
+		CacheSize:      env.Int("HISTORY_CACHE_SIZE", 100000),
+		LocalStoreSize: env.Int("HISTORY_LOCAL_STORE_SIZE", 100000),
+		Defaults: DefaultsConfig{
+			Currency:       env.String("HISTORY_DEFAULT_CURRENCY", ""),
+			BaselineAmount: env.String("HISTORY_DEFAULT_BASELINE_AMOUNT", ""),
+		},
+	}
+}
@@ -0,0 +1,30 @@
+package out
+
+import (
+	"context"
+	"fraud-scoring/internal/domain/history"
+	"time"
+)
+
+// DefaultUserTransactions answers every lookup with the configured defaults. It is meant to close the chain so that
+// buyers are still scored while no other provider is available. Left empty, the defaults are no history at all and the
+// criteria comparing with the history don't apply.
+type DefaultUserTransactions struct {
+	defaults DefaultsConfig
+}
+
+func (dut *DefaultUserTransactions) LastOrder(context.Context, string) (*history.LastOrder, error) {
+	return &history.LastOrder{Currency: dut.defaults.Currency}, nil
+}
+
+func (dut *DefaultUserTransactions) AverageTransactions(_ context.Context, _ string, at time.Time) (*history.AveragePayment, error) {
+	return &history.AveragePayment{Month: at.Format(history.MonthLayout), Amount: dut.defaults.BaselineAmount}, nil
+}
+
+func (dut *DefaultUserTransactions) AverageBaseline(context.Context, string, time.Time, int) (*history.AverageBaseline, error) {
+	return &history.AverageBaseline{Amount: dut.defaults.BaselineAmount}, nil
+}
+
+func NewDefaultUserTransactions(config *HistoryConfig) *DefaultUserTransactions {
+	return &DefaultUserTransactions{defaults: config.Defaults}
+}
@@ -0,0 +1,187 @@
+package out
+
+import (
+	"context"
+	"errors"
+	"fraud-scoring/internal/domain"
+	"fraud-scoring/internal/domain/history"
+	"fraud-scoring/internal/domain/repositories"
+	"fraud-scoring/internal/infra/lru"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// localStoreMonths is how many monthly totals are kept per buyer, enough for a yearly baseline.
+const localStoreMonths = 13
+
+// ErrNoLocalHistory only tells that the buyer wasn't scored here lately, unlike a HistoryNotFound the chain goes on.
+var ErrNoLocalHistory = errors.New("no locally recorded history for buyer")
+
+type buyerHistory struct {
+	last   *history.LastOrder
+	months map[string]*monthTotal
+	// payments are the months of the recorded payments, so one stored again is not counted twice.
+	payments map[string]string
+}
+
+type monthTotal struct {
+	sum   float64
+	count int
+	asOf  time.Time
+}
+
+// LocalUserTransactionsStore keeps the history of the buyers this instance scored, so it can still answer when neither
+// the remote service nor the cache can. It is fed by RecordingTransactionScoreCard.
+type LocalUserTransactionsStore struct {
+	mu     sync.Mutex
+	buyers *lru.Cache[string, *buyerHistory]
+}
+
+// Record adds a scored transaction to the history of its buyer.
+func (lus *LocalUserTransactionsStore) Record(order *domain.TransactionAnalysis) {
+	lus.mu.Lock()
+	defer lus.mu.Unlock()
+	document := order.Participants.Buyer.Document
+	bh, ok := lus.buyers.Get(document)
+	if !ok {
+		bh = &buyerHistory{months: map[string]*monthTotal{}, payments: map[string]string{}}
+	}
+	lus.buyers.Put(document, bh)
+	if _, recorded := bh.payments[order.Payment.Id]; order.Payment.Id != "" && recorded {
+		return
+	}
+
+	at := order.Order.At
+	if bh.last == nil || !at.Before(bh.last.Provenance.AsOf) {
+		bh.last = &history.LastOrder{
+			SellerId:   order.Participants.Seller.SellerId,
+			Currency:   order.Payment.Currency,
+			Amount:     order.Payment.Amount,
+			At:         at,
+			Provenance: history.Provenance{AsOf: at},
+		}
+	}
+
+	amount, err := strconv.ParseFloat(order.Payment.Amount, 64)
+	if err != nil {
+		return
+	}
+	month := at.Format(history.MonthLayout)
+	total, ok := bh.months[month]
+	if !ok {
+		total = &monthTotal{}
+		bh.months[month] = total
+	}
+	total.sum += amount
+	total.count++
+	if at.After(total.asOf) {
+		total.asOf = at
+	}
+	if order.Payment.Id != "" {
+		bh.payments[order.Payment.Id] = month
+	}
+	bh.prune()
+}
+
+// prune drops the oldest months beyond localStoreMonths, along with their payments.
+func (bh *buyerHistory) prune() {
+	if len(bh.months) <= localStoreMonths {
+		return
+	}
+	months := make([]string, 0, len(bh.months))
+	for month := range bh.months {
+		months = append(months, month)
+	}
+	sort.Strings(months)
+	for _, month := range months[:len(months)-localStoreMonths] {
+		delete(bh.months, month)
+	}
+	for payment, month := range bh.payments {
+		if _, ok := bh.months[month]; !ok {
+			delete(bh.payments, payment)
+		}
+	}
+}
+
+func (lus *LocalUserTransactionsStore) LastOrder(_ context.Context, document string) (*history.LastOrder, error) {
+	lus.mu.Lock()
+	defer lus.mu.Unlock()
+	bh, ok := lus.buyers.Get(document)
+	if !ok || bh.last == nil {
+		return nil, ErrNoLocalHistory
+	}
+	lo := *bh.last
+	return &lo, nil
+}
+
+func (lus *LocalUserTransactionsStore) AverageTransactions(_ context.Context, document string, at time.Time) (*history.AveragePayment, error) {
+	lus.mu.Lock()
+	defer lus.mu.Unlock()
+	if avg := lus.average(document, at); avg != nil {
+		return avg, nil
+	}
+	return nil, ErrNoLocalHistory
+}
+
+func (lus *LocalUserTransactionsStore) AverageBaseline(_ context.Context, document string, at time.Time, months int) (*history.AverageBaseline, error) {
+	lus.mu.Lock()
+	defer lus.mu.Unlock()
+	samples := make([]*history.AveragePayment, months)
+	var asOf time.Time
+	for i := range samples {
+		samples[i] = lus.average(document, time.Date(at.Year(), at.Month()-time.Month(i+1), 1, 0, 0, 0, 0, at.Location()))
+		if samples[i] != nil && samples[i].Provenance.AsOf.After(asOf) {
+			asOf = samples[i].Provenance.AsOf
+		}
+	}
+	baseline, err := history.NewAverageBaseline(samples)
+	if err != nil {
+		return nil, ErrNoLocalHistory
+	}
+	baseline.Provenance.AsOf = asOf
+	return baseline, nil
+}
+
+// average must be called with mu held.
+func (lus *LocalUserTransactionsStore) average(document string, at time.Time) *history.AveragePayment {
+	bh, ok := lus.buyers.Get(document)
+	if !ok {
+		return nil
+	}
+	month := at.Format(history.MonthLayout)
+	total, ok := bh.months[month]
+	if !ok {
+		return nil
+	}
+	return &history.AveragePayment{
+		Month:      month,
+		Amount:     strconv.FormatFloat(total.sum/float64(total.count), 'f', 2, 64),
+		Total:      strconv.FormatFloat(total.sum, 'f', 2, 64),
+		Count:      total.count,
+		Provenance: history.Provenance{AsOf: total.asOf},
+	}
+}
+
+func NewLocalUserTransactionsStore(config *HistoryConfig) *LocalUserTransactionsStore {
+	return &LocalUserTransactionsStore{buyers: lru.New[string, *buyerHistory](config.LocalStoreSize)}
+}
+
+// RecordingTransactionScoreCard records every transaction stored by next in the local store.
+type RecordingTransactionScoreCard struct {
+	next  repositories.TransactionScoreCard
+	store *LocalUserTransactionsStore
+}
+
+func (rtsc *RecordingTransactionScoreCard) Store(ctx context.Context, card *domain.ScoringResult) error {
+	if err := rtsc.next.Store(ctx, card); err != nil {
+		return err
+	}
+	rtsc.store.Record(&card.Transaction)
+	return nil
+}
+
+func NewRecordingTransactionScoreCard(next repositories.TransactionScoreCard, store *LocalUserTransactionsStore) *RecordingTransactionScoreCard {
+	return &RecordingTransactionScoreCard{next: next, store: store}
+}
@@ -0,0 +1,97 @@
+package out
+
+import (
+	"context"
+	"errors"
+	"fraud-scoring/internal/domain"
+	"testing"
+	"time"
+)
+
+func newScoredOrder(document, seller, amount string, at time.Time) *domain.TransactionAnalysis {
+	return &domain.TransactionAnalysis{
+		Participants: domain.Participants{
+			Buyer:  domain.BuyerInfo{Document: document},
+			Seller: domain.SellerInfo{SellerId: seller},
+		},
+		Order:   domain.Checkout{At: at},
+		Payment: domain.Payment{Amount: amount, Currency: "BRL"},
+	}
+}
+
+func TestLocalUserTransactionsStore_BuildsHistoryFromScoredTransactions(t *testing.T) {
+	store := NewLocalUserTransactionsStore(testHistoryConfig())
+	ctx := context.Background()
+	store.Record(newScoredOrder("12345678901", "seller-1", "100.00", time.Date(2024, time.February, 10, 0, 0, 0, 0, time.UTC)))
+	store.Record(newScoredOrder("12345678901", "seller-2", "200.00", time.Date(2024, time.February, 20, 0, 0, 0, 0, time.UTC)))
+	// Out of order delivery must not replace the last order
+	store.Record(newScoredOrder("12345678901", "seller-0", "60.00", time.Date(2024, time.January, 5, 0, 0, 0, 0, time.UTC)))
+
+	lo, err := store.LastOrder(ctx, "12345678901")
+	if err != nil || lo.SellerId != "seller-2" || !lo.At.Equal(time.Date(2024, time.February, 20, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the latest transaction as last order, got %+v, %v", lo, err)
+	}
+
+	avg, err := store.AverageTransactions(ctx, "12345678901", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil || avg.Amount != "150.00" || avg.Total != "300.00" || avg.Count != 2 {
+		t.Errorf("Expected the February average, total and count, got %+v, %v", avg, err)
+	}
+
+	baseline, err := store.AverageBaseline(ctx, "12345678901", time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), 2)
+	if err != nil {
+		t.Fatalf("Expected baseline, got %v", err)
+	}
+	// (150*2 + 60*1) / 3
+	if baseline.Amount != "120.00" {
+		t.Errorf("Expected baseline 120.00, got %s", baseline.Amount)
+	}
+
+	if _, err := store.LastOrder(ctx, "00000000000"); !errors.Is(err, ErrNoLocalHistory) {
+		t.Errorf("Expected ErrNoLocalHistory for an unknown buyer, got %v", err)
+	}
+}
+
+type failingScoreCard struct{ err error }
+
+func (f *failingScoreCard) Store(context.Context, *domain.ScoringResult) error { return f.err }
+
+func TestRecordingTransactionScoreCard_RecordsOnlyStoredCards(t *testing.T) {
+	store := NewLocalUserTransactionsStore(testHistoryConfig())
+	next := &failingScoreCard{err: errors.New("broker down")}
+	recording := NewRecordingTransactionScoreCard(next, store)
+	card := &domain.ScoringResult{Transaction: *newScoredOrder("12345678901", "seller-1", "100.00", time.Now())}
+
+	if err := recording.Store(context.Background(), card); err == nil {
+		t.Fatal("Expected the store error to be returned")
+	}
+	if _, err := store.LastOrder(context.Background(), "12345678901"); err == nil {
+		t.Error("Expected a card that was not stored to be left out")
+	}
+
+	next.err = nil
+	if err := recording.Store(context.Background(), card); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := store.LastOrder(context.Background(), "12345678901"); err != nil {
+		t.Errorf("Expected stored card to be recorded, got %v", err)
+	}
+}
+
+func TestLocalUserTransactionsStore_CountsPaymentOnce(t *testing.T) {
+	store := NewLocalUserTransactionsStore(testHistoryConfig())
+	at := time.Date(2024, time.February, 10, 0, 0, 0, 0, time.UTC)
+	order := newScoredOrder("12345678901", "seller-1", "100.00", at)
+	order.Payment.Id = "pay-1"
+	store.Record(order)
+	// The same score card published again
+	store.Record(order)
+	store.Record(newScoredOrder("12345678901", "seller-1", "200.00", at))
+
+	avg, err := store.AverageTransactions(context.Background(), "12345678901", at)
+	if err != nil {
+		t.Fatalf("Expected average, got %v", err)
+	}
+	if avg.Amount != "150.00" {
+		t.Errorf("Expected average 150.00, got %s", avg.Amount)
+	}
+}
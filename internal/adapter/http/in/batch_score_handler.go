@@ -0,0 +1,143 @@
+package in
+
+import (
+	"bufio"
+	"encoding/json"
+	stderrors "errors"
+	"fraud-scoring/internal/domain"
+	"fraud-scoring/internal/domain/application"
+	"fraud-scoring/internal/domain/application/errors"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const ndjsonContentType = "application/x-ndjson"
+
+// batchScoreLine is one line of the POST /score/transactions response, it carries either the result or the error of
+// the transaction read at Index.
+type batchScoreLine struct {
+	Index     int              `json:"index"`
+	PaymentId string           `json:"paymentId,omitempty"`
+	Result    *scoringResponse `json:"result,omitempty"`
+	Error     *ErrorResponse   `json:"error,omitempty"`
+}
+
+// itemError rejects a single line of a batch before it is scored.
+type itemError struct {
+	code    string
+	message string
+	details map[string]any
+}
+
+func (e itemError) Error() string {
+	return e.message
+}
+
+// BatchScoreHandler serves POST /score/transactions. The request body holds one scoreTransactionRequest per line,
+// the response streams back one batchScoreLine per transaction, in the same order, as soon as it is scored.
+type BatchScoreHandler struct {
+	scr *application.PaymentRiskScoring
+	tc  *domain.TransactionComponent
+	log *zap.Logger
+}
+
+func (bsh *BatchScoreHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	rc := http.NewResponseController(w)
+	// Results are written while the batch is still being read, and a backfill outlives the server timeouts.
+	_ = rc.EnableFullDuplex()
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	ctx := r.Context()
+	items := make(chan application.BatchItem)
+	results := make(chan application.BatchResult)
+	go bsh.read(r, items)
+	go bsh.scr.AssessBatch(ctx, items, results)
+
+	w.Header().Set("Content-Type", ndjsonContentType)
+	w.WriteHeader(http.StatusOK)
+	enc := json.NewEncoder(w)
+	index := 0
+	gone := false
+	for res := range results {
+		if gone {
+			// Keep draining so AssessBatch returns, the request context is canceled right after.
+			continue
+		}
+		line := batchScoreLine{Index: index, PaymentId: res.Key}
+		if res.Err == nil {
+			line.Result = toScoringResponse(res.Result)
+		} else {
+			line.Error = bsh.toErrorResponse(res)
+		}
+		if err := enc.Encode(line); err != nil {
+			bsh.log.Warn("batch scoring client went away", zap.Int("index", index), zap.Error(err))
+			gone = true
+			continue
+		}
+		_ = rc.Flush()
+		index++
+	}
+}
+
+// read sends one item per non-blank line of the request body to items and closes it at the end of the body.
+func (bsh *BatchScoreHandler) read(r *http.Request, items chan<- application.BatchItem) {
+	defer close(items)
+	send := func(item application.BatchItem) bool {
+		select {
+		case items <- item:
+			return true
+		case <-r.Context().Done():
+			return false
+		}
+	}
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRequestBody)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		if !send(bsh.item(scanner.Bytes())) {
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		// The rest of the body can't be split into lines anymore, the batch ends with the reason.
+		send(application.BatchItem{Err: itemError{code: codeInvalidRequest, message: "failed to read batch", details: map[string]any{"reason": err.Error()}}})
+	}
+}
+
+func (bsh *BatchScoreHandler) item(line []byte) application.BatchItem {
+	req := &scoreTransactionRequest{}
+	if err := json.Unmarshal(line, req); err != nil {
+		return application.BatchItem{Err: itemError{code: codeInvalidRequest, message: "invalid request line", details: map[string]any{"reason": err.Error()}}}
+	}
+	if req.Transaction == nil {
+		return application.BatchItem{Err: itemError{code: codeInvalidRequest, message: "transaction is required", details: map[string]any{"field": "transaction"}}}
+	}
+	return application.BatchItem{Key: req.Transaction.Payment.Id, Transaction: req.Transaction, Vet: vet(bsh.tc)}
+}
+
+func (bsh *BatchScoreHandler) toErrorResponse(res application.BatchResult) *ErrorResponse {
+	var rejected itemError
+	if stderrors.As(res.Err, &rejected) {
+		return &ErrorResponse{Error: rejected.message, Code: rejected.code, Timestamp: time.Now().UTC(), Details: rejected.details}
+	}
+	var invalid errors.TransactionRejected
+	if stderrors.As(res.Err, &invalid) {
+		return &ErrorResponse{Error: "transaction is not valid", Code: codeValidationFailed, Timestamp: time.Now().UTC(),
+			Details: map[string]any{"errors": invalid.Errors}}
+	}
+	bsh.log.Error("error to score transaction", zap.String("id", res.Key), zap.Error(res.Err))
+	_, code, message := scoringError(res.Err)
+	return &ErrorResponse{Error: message, Code: code, Timestamp: time.Now().UTC()}
+}
+
+func NewBatchScoreHandler(scr *application.PaymentRiskScoring, tc *domain.TransactionComponent, log *zap.Logger) *BatchScoreHandler {
+	return &BatchScoreHandler{scr: scr, tc: tc, log: log}
+}
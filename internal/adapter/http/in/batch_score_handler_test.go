@@ -0,0 +1,124 @@
+package in
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func batchLine(body string) string {
+	return strings.Join(strings.Fields(body), " ")
+}
+
+func decodeBatch(t *testing.T, body string) []batchScoreLine {
+	var lines []batchScoreLine
+	dec := json.NewDecoder(strings.NewReader(body))
+	for dec.More() {
+		var line batchScoreLine
+		if err := dec.Decode(&line); err != nil {
+			t.Fatalf("Failed to decode response line: %v", err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestBatchScoreHandler_StreamsResultsInOrder(t *testing.T) {
+	valid := batchLine(scoreRequestBody(time.Now().Add(-time.Minute)))
+	body := strings.Join([]string{
+		valid,
+		`{"transaction":`,
+		"",
+		batchLine(scoreRequestBody(time.Now().Add(-48 * time.Hour))),
+		`{}`,
+		valid,
+	}, "\n")
+
+	rec := serve(newTestRouter(t, &stubHistory{}), http.MethodPost, "/v1/score/transactions", body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != ndjsonContentType {
+		t.Errorf("Expected %s, got %s", ndjsonContentType, ct)
+	}
+
+	lines := decodeBatch(t, rec.Body.String())
+	expected := []string{"", codeInvalidRequest, codeValidationFailed, codeInvalidRequest, ""}
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines, blank ones skipped, got %d: %s", len(expected), len(lines), rec.Body)
+	}
+	for i, line := range lines {
+		if line.Index != i {
+			t.Errorf("Expected line %d to carry its index, got %d", i, line.Index)
+		}
+		if expected[i] == "" {
+			if line.Result == nil || line.Error != nil || line.PaymentId != "payment-789" {
+				t.Errorf("Expected line %d to be scored, got %+v", i, line)
+			}
+			continue
+		}
+		if line.Result != nil || line.Error == nil || line.Error.Code != expected[i] {
+			t.Errorf("Expected line %d to fail with %s, got %+v", i, expected[i], line)
+		}
+	}
+	if errs, _ := lines[2].Error.Details["errors"].([]any); len(errs) == 0 {
+		t.Errorf("Expected the validation errors in details, got %v", lines[2].Error.Details)
+	}
+}
+
+func TestBatchScoreHandler_HistoryErrorFailsOnlyItsItem(t *testing.T) {
+	body := batchLine(scoreRequestBody(time.Now()))
+	rec := serve(newTestRouter(t, &stubHistory{err: errors.New("connection refused")}), http.MethodPost, "/v1/score/transactions", body+"\n"+body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	lines := decodeBatch(t, rec.Body.String())
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if line.Error == nil || line.Error.Code != codeHistoryUnavailable {
+			t.Errorf("Expected %s, got %+v", codeHistoryUnavailable, line)
+		}
+	}
+}
+
+func TestBatchScoreHandler_WrongMethod(t *testing.T) {
+	rec := serve(newTestRouter(t, &stubHistory{}), http.MethodGet, "/v1/score/transactions", "")
+	if rec.Code != http.StatusMethodNotAllowed || decodeError(t, rec).Code != codeMethodNotAllowed {
+		t.Errorf("Expected documented 405 error, got %d", rec.Code)
+	}
+}
+
+func TestBatchScoreHandler_AnswersBeforeTheBatchEnds(t *testing.T) {
+	srv := httptest.NewServer(newTestRouter(t, &stubHistory{}))
+	defer srv.Close()
+
+	body, pipe := io.Pipe()
+	defer pipe.Close()
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/v1/score/transactions", body)
+	req.Header.Set("Content-Type", ndjsonContentType)
+	go func() {
+		_, _ = io.WriteString(pipe, batchLine(scoreRequestBody(time.Now()))+"\n")
+	}()
+
+	res, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("Failed to send the batch: %v", err)
+	}
+	defer res.Body.Close()
+
+	// The request body is still open, the first result must arrive anyway.
+	var line batchScoreLine
+	if err := json.NewDecoder(res.Body).Decode(&line); err != nil {
+		t.Fatalf("Expected the first result while the batch is open, got %v", err)
+	}
+	if line.Result == nil {
+		t.Errorf("Expected the first transaction to be scored, got %+v", line.Error)
+	}
+}
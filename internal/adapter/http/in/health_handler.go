@@ -0,0 +1,63 @@
+package in
+
+import (
+	"fraud-scoring/internal/infra/health"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	healthPath    = basePath + "/health"
+	livenessPath  = healthPath + "/live"
+	readinessPath = healthPath + "/ready"
+)
+
+// HealthResponse is the health body documented in api/openapi.yaml.
+type HealthResponse struct {
+	Status       string            `json:"status"`
+	Timestamp    time.Time         `json:"timestamp"`
+	Version      string            `json:"version"`
+	Dependencies map[string]string `json:"dependencies"`
+	Errors       map[string]string `json:"errors,omitempty"`
+}
+
+// HealthHandler answers the orchestrator probes. /health and /health/ready run the readiness checks, /health/live the
+// liveness ones. Both answer 503 when any check fails.
+type HealthHandler struct {
+	checker *health.Checker
+	log     *zap.Logger
+}
+
+func (hh *HealthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	var report *health.Report
+	switch r.URL.Path {
+	case livenessPath:
+		report = hh.checker.Live(r.Context())
+	case healthPath, readinessPath:
+		report = hh.checker.Ready(r.Context())
+	default:
+		writeError(w, http.StatusNotFound, codeNotFound, "no route for "+r.URL.Path, nil)
+		return
+	}
+	status := http.StatusOK
+	if !report.Healthy() {
+		status = http.StatusServiceUnavailable
+		hh.log.Warn("health check failed", zap.String("probe", r.URL.Path), zap.Any("errors", report.Errors))
+	}
+	writeJSON(w, status, HealthResponse{
+		Status:       report.Status,
+		Timestamp:    time.Now().UTC(),
+		Version:      report.Version,
+		Dependencies: report.Dependencies,
+		Errors:       report.Errors,
+	})
+}
+
+func NewHealthHandler(checker *health.Checker, log *zap.Logger) *HealthHandler {
+	return &HealthHandler{checker: checker, log: log}
+}
@@ -0,0 +1,81 @@
+package in
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fraud-scoring/internal/infra/health"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func newHealthHandler(t *testing.T) *HealthHandler {
+	checker := health.NewChecker(&health.Config{Timeout: 100 * time.Millisecond, Version: "1.2.3"})
+	checker.Readiness("kafka", func(context.Context) error { return nil })
+	checker.Readiness("kafka", func(context.Context) error { return errors.New("not a member of the kafka consumer group") })
+	checker.Readiness("grpc", func(context.Context) error { return nil })
+	checker.Liveness("kafka", func(context.Context) error { return nil })
+	return NewHealthHandler(checker, zaptest.NewLogger(t))
+}
+
+func decodeHealth(t *testing.T, body *json.Decoder) HealthResponse {
+	var res HealthResponse
+	if err := body.Decode(&res); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	return res
+}
+
+func TestHealthHandler_Readiness(t *testing.T) {
+	for _, path := range []string{"/v1/health", "/v1/health/ready"} {
+		t.Run(path, func(t *testing.T) {
+			rec := serve(newHealthHandler(t), http.MethodGet, path, "")
+			if rec.Code != http.StatusServiceUnavailable {
+				t.Fatalf("Expected 503, got %d: %s", rec.Code, rec.Body)
+			}
+			res := decodeHealth(t, json.NewDecoder(rec.Body))
+			if res.Status != health.StatusUnhealthy || res.Version != "1.2.3" {
+				t.Errorf("Unexpected response %+v", res)
+			}
+			if res.Dependencies["kafka"] != health.StatusUnhealthy || res.Dependencies["grpc"] != health.StatusHealthy {
+				t.Errorf("Expected kafka unhealthy and grpc healthy, got %v", res.Dependencies)
+			}
+			if res.Errors["kafka"] == "" {
+				t.Errorf("Expected the kafka failure in the errors, got %v", res.Errors)
+			}
+		})
+	}
+}
+
+func TestHealthHandler_Liveness(t *testing.T) {
+	rec := serve(newHealthHandler(t), http.MethodGet, "/v1/health/live", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	res := decodeHealth(t, json.NewDecoder(rec.Body))
+	if res.Status != health.StatusHealthy || res.Timestamp.IsZero() {
+		t.Errorf("Unexpected response %+v", res)
+	}
+}
+
+func TestHealthHandler_CheckTimeout(t *testing.T) {
+	checker := health.NewChecker(&health.Config{Timeout: 10 * time.Millisecond})
+	checker.Readiness("grpc", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	rec := serve(NewHealthHandler(checker, zaptest.NewLogger(t)), http.MethodGet, "/v1/health", "")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503, got %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestHealthHandler_MethodNotAllowed(t *testing.T) {
+	rec := serve(newHealthHandler(t), http.MethodPost, "/v1/health", "")
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("Expected 405, got %d", rec.Code)
+	}
+}
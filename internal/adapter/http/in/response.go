@@ -0,0 +1,52 @@
+package in
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+const (
+	codeInvalidRequest      = "INVALID_REQUEST"
+	codeValidationFailed    = "VALIDATION_FAILED"
+	codeMethodNotAllowed    = "METHOD_NOT_ALLOWED"
+	codeNotFound            = "NOT_FOUND"
+	codeHistoryUnavailable  = "HISTORY_UNAVAILABLE"
+	codeHistoryTimeout      = "HISTORY_TIMEOUT"
+	codeScoringTimeout      = "SCORING_TIMEOUT"
+	codeIdempotencyConflict = "IDEMPOTENCY_CONFLICT"
+	codeInternalError       = "INTERNAL_ERROR"
+)
+
+// ErrorResponse is the error body documented in api/openapi.yaml.
+type ErrorResponse struct {
+	Error     string         `json:"error"`
+	Code      string         `json:"code"`
+	Timestamp time.Time      `json:"timestamp"`
+	Details   map[string]any `json:"details,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string, details map[string]any) {
+	writeJSON(w, status, ErrorResponse{
+		Error:     message,
+		Code:      code,
+		Timestamp: time.Now().UTC(),
+		Details:   details,
+	})
+}
+
+// allowMethod answers 405 and returns false when r does not use method.
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeError(w, http.StatusMethodNotAllowed, codeMethodNotAllowed, "method "+r.Method+" is not allowed", nil)
+	return false
+}
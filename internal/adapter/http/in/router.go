@@ -0,0 +1,35 @@
+package in
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// basePath is the version prefix of the servers listed in api/openapi.yaml.
+const basePath = "/v1"
+
+// Router maps the REST API paths to their handlers.
+type Router struct {
+	mux *http.ServeMux
+}
+
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rt.mux.ServeHTTP(w, r)
+}
+
+func NewRouter(score *ScoreTransactionHandler, batch *BatchScoreHandler, users *UserTransactionsHandler, health *HealthHandler) *Router {
+	mux := http.NewServeMux()
+	mux.Handle(basePath+"/score/transaction", score)
+	mux.Handle(basePath+"/score/transactions", batch)
+	mux.Handle(usersPath, users)
+	mux.Handle(healthPath, health)
+	mux.Handle(healthPath+"/", health)
+	// Prometheus scrapes /metrics by default, the versioned path is the one documented in api/openapi.yaml
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.Handle(basePath+"/metrics", promhttp.Handler())
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, codeNotFound, "no route for "+r.URL.Path, nil)
+	})
+	return &Router{mux: mux}
+}
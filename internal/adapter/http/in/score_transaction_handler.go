@@ -0,0 +1,126 @@
+package in
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fraud-scoring/internal/domain"
+	"fraud-scoring/internal/domain/application"
+	"fraud-scoring/internal/domain/application/errors"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const maxRequestBody = 1 << 20
+
+type scoreTransactionRequest struct {
+	Transaction *domain.TransactionAnalysis `json:"transaction"`
+}
+
+type scoringResponse struct {
+	Score       scoreCardResponse          `json:"score"`
+	Transaction domain.TransactionAnalysis `json:"transaction"`
+	RiskLevel   string                     `json:"riskLevel"`
+	Decision    domain.Decision            `json:"decision"`
+	Provenance  domain.ScoreProvenance     `json:"provenance"`
+	Timestamp   time.Time                  `json:"timestamp"`
+}
+
+type scoreCardResponse struct {
+	domain.ScoreCard
+	OverallScore int `json:"overallScore"`
+}
+
+// ScoreTransactionHandler serves POST /score/transaction through the same scoring pipeline as the Kafka flow.
+type ScoreTransactionHandler struct {
+	scr *application.PaymentRiskScoring
+	tc  *domain.TransactionComponent
+	log *zap.Logger
+}
+
+func (sth *ScoreTransactionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	req := &scoreTransactionRequest{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(req); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, "invalid request body", map[string]any{"reason": err.Error()})
+		return
+	}
+	if req.Transaction == nil {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, "transaction is required", map[string]any{"field": "transaction"})
+		return
+	}
+
+	result, err := sth.scr.VettedAssessment(r.Context(), req.Transaction, vet(sth.tc))
+	var rejected errors.TransactionRejected
+	if stderrors.As(err, &rejected) {
+		writeError(w, http.StatusBadRequest, codeValidationFailed, "transaction is not valid", map[string]any{"errors": rejected.Errors})
+		return
+	}
+	if err != nil {
+		sth.log.Error("error to score transaction", zap.String("id", req.Transaction.Payment.Id), zap.Error(err))
+		writeScoringError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toScoringResponse(result))
+}
+
+// vet validates the transactions once they are known not to be duplicates, so a client retrying a transaction already
+// scored gets its scorecard again however old it is now.
+func vet(tc *domain.TransactionComponent) application.Vet {
+	return func(_ context.Context, transaction *domain.TransactionAnalysis) error {
+		validation, err := tc.Component(transaction)
+		if err != nil {
+			return err
+		}
+		if !validation.IsValid {
+			return errors.TransactionRejected{Errors: validation.Errors}
+		}
+		return nil
+	}
+}
+
+func toScoringResponse(result *domain.ScoringResult) *scoringResponse {
+	return &scoringResponse{
+		Score:       scoreCardResponse{ScoreCard: result.Score, OverallScore: result.Score.Total()},
+		Transaction: result.Transaction,
+		RiskLevel:   result.Decision.RiskLevel(),
+		Decision:    result.Decision,
+		Provenance:  result.Provenance,
+		Timestamp:   time.Now().UTC(),
+	}
+}
+
+func writeScoringError(w http.ResponseWriter, err error) {
+	if stderrors.Is(err, context.Canceled) {
+		// The client went away, nobody reads the answer.
+		return
+	}
+	status, code, message := scoringError(err)
+	writeError(w, status, code, message, nil)
+}
+
+// scoringError maps an assessment failure to its HTTP status, error code and message.
+func scoringError(err error) (int, string, string) {
+	var budget errors.ScoringBudgetExceeded
+	var lastOrder errors.LastOrderNotFound
+	var average errors.AverageTransactionsNotFound
+	var conflict errors.IdempotencyConflict
+	switch {
+	case stderrors.As(err, &conflict):
+		return http.StatusConflict, codeIdempotencyConflict, err.Error()
+	case stderrors.As(err, &budget):
+		return http.StatusGatewayTimeout, codeScoringTimeout, err.Error()
+	case stderrors.As(err, &lastOrder), stderrors.As(err, &average):
+		return http.StatusServiceUnavailable, codeHistoryUnavailable, err.Error()
+	default:
+		return http.StatusInternalServerError, codeInternalError, "failed to score transaction"
+	}
+}
+
+func NewScoreTransactionHandler(scr *application.PaymentRiskScoring, tc *domain.TransactionComponent, log *zap.Logger) *ScoreTransactionHandler {
+	return &ScoreTransactionHandler{scr: scr, tc: tc, log: log}
+}
@@ -0,0 +1,239 @@
+package in
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	iout "fraud-scoring/internal/adapter/idempotency/out"
+	"fraud-scoring/internal/domain"
+	"fraud-scoring/internal/domain/application"
+	scoringerrors "fraud-scoring/internal/domain/application/errors"
+	"fraud-scoring/internal/domain/history"
+	"fraud-scoring/internal/domain/repositories"
+	"fraud-scoring/internal/infra/health"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+type stubHistory struct {
+	err error
+}
+
+func (s *stubHistory) LastOrder(context.Context, string) (*history.LastOrder, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &history.LastOrder{SellerId: "seller-123", Currency: "BRL", Amount: "100.00", At: time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)}, nil
+}
+
+func (s *stubHistory) AverageTransactions(_ context.Context, _ string, at time.Time) (*history.AveragePayment, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &history.AveragePayment{Month: at.Format(history.MonthLayout), Amount: "100.00", Total: "1500.00", Count: 15}, nil
+}
+
+func (s *stubHistory) AverageBaseline(context.Context, string, time.Time, int) (*history.AverageBaseline, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &history.AverageBaseline{Amount: "100.00", Provenance: history.Provenance{Source: history.SourceGrpc}}, nil
+}
+
+type stubScoreCard struct{}
+
+func (stubScoreCard) Store(context.Context, *domain.ScoringResult) error { return nil }
+
+func newTestRouter(t *testing.T, utr *stubHistory) *Router {
+	return newDedupingTestRouter(t, utr, nil)
+}
+
+// newDedupingTestRouter scores through results, to dedupe the transactions by their idempotency keys.
+func newDedupingTestRouter(t *testing.T, utr *stubHistory, results repositories.ScoringResults) *Router {
+	log := zaptest.NewLogger(t)
+	config := &application.ScoringConfig{
+		Budget:         time.Second,
+		BaselineMonths: 3,
+		Decision:       domain.DecisionPolicy{ReviewAt: -3, DeclineAt: -6},
+	}
+	scr := application.NewPaymentRiskScoring(utr, stubScoreCard{}, results, config, log)
+	tc := domain.NewTransactionComponent(24*time.Hour, 0.01, 1000000, []string{"BRL", "USD"})
+	checker := health.NewChecker(&health.Config{Timeout: time.Second, Version: "test"})
+	return NewRouter(NewScoreTransactionHandler(scr, tc, log), NewBatchScoreHandler(scr, tc, log), NewUserTransactionsHandler(utr, log), NewHealthHandler(checker, log))
+}
+
+func scoreRequestBody(at time.Time) string {
+	return `{"transaction": {
+		"participants": {"buyer": {"document": "12345678901", "name": "John Doe"}, "seller": {"sellerId": "seller-123"}},
+		"order": {"id": "order-456", "paymentType": {"cardInfo": "****1234", "token": "tok_123"}, "at": "` + at.Format(time.RFC3339) + `"},
+		"payment": {"id": "payment-789", "amount": "100.00", "currency": "BRL", "status": "completed"}
+	}}`
+}
+
+func serve(router http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return rec
+}
+
+func decodeError(t *testing.T, rec *httptest.ResponseRecorder) ErrorResponse {
+	var res ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&res); err != nil {
+		t.Fatalf("Expected an error body, got %v", err)
+	}
+	if res.Timestamp.IsZero() || res.Error == "" {
+		t.Errorf("Expected error and timestamp in the error body, got %+v", res)
+	}
+	return res
+}
+
+func TestScoreTransactionHandler_Success(t *testing.T) {
+	router := newTestRouter(t, &stubHistory{})
+
+	rec := serve(router, http.MethodPost, "/v1/score/transaction", scoreRequestBody(time.Now().Add(-time.Minute)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	var res map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&res); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	for _, field := range []string{"score", "transaction", "riskLevel", "decision", "provenance", "timestamp"} {
+		if _, ok := res[field]; !ok {
+			t.Errorf("Expected %s in the response", field)
+		}
+	}
+	score := res["score"].(map[string]any)
+	for _, field := range []string{"valueScore", "sellerScore", "averageValueScore", "currencyScore", "overallScore"} {
+		if _, ok := score[field]; !ok {
+			t.Errorf("Expected score.%s in the response", field)
+		}
+	}
+}
+
+func TestScoreTransactionHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		history        *stubHistory
+		method         string
+		body           string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "Malformed body",
+			history:        &stubHistory{},
+			method:         http.MethodPost,
+			body:           `{"transaction":`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   codeInvalidRequest,
+		},
+		{
+			name:           "Missing transaction",
+			history:        &stubHistory{},
+			method:         http.MethodPost,
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   codeInvalidRequest,
+		},
+		{
+			name:           "Transaction too old",
+			history:        &stubHistory{},
+			method:         http.MethodPost,
+			body:           scoreRequestBody(time.Now().Add(-48 * time.Hour)),
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   codeValidationFailed,
+		},
+		{
+			name:           "History unavailable",
+			history:        &stubHistory{err: errors.New("connection refused")},
+			method:         http.MethodPost,
+			body:           scoreRequestBody(time.Now()),
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedCode:   codeHistoryUnavailable,
+		},
+		{
+			name:           "Wrong method",
+			history:        &stubHistory{},
+			method:         http.MethodGet,
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedCode:   codeMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(newTestRouter(t, tt.history), tt.method, "/v1/score/transaction", tt.body)
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if res := decodeError(t, rec); res.Code != tt.expectedCode {
+				t.Errorf("Expected code %s, got %s", tt.expectedCode, res.Code)
+			}
+		})
+	}
+}
+
+func TestScoringError_IdempotencyConflict(t *testing.T) {
+	status, code, _ := scoringError(scoringerrors.IdempotencyConflict{Key: "checkout-key/", Err: scoringerrors.ErrIdempotencyKeyReused})
+	if status != http.StatusConflict || code != codeIdempotencyConflict {
+		t.Errorf("Expected 409 %s, got %d %s", codeIdempotencyConflict, status, code)
+	}
+}
+
+func TestScoreTransactionHandler_ValidationErrorsInDetails(t *testing.T) {
+	body := strings.Replace(scoreRequestBody(time.Now()), `"currency": "BRL"`, `"currency": "JPY"`, 1)
+	rec := serve(newTestRouter(t, &stubHistory{}), http.MethodPost, "/v1/score/transaction", body)
+
+	res := decodeError(t, rec)
+	errs, _ := res.Details["errors"].([]any)
+	if len(errs) != 1 || errs[0] != "unsupported currency" {
+		t.Errorf("Expected the validation errors in details, got %v", res.Details)
+	}
+}
+
+func TestScoreTransactionHandler_RetryOfAnOldScoredTransaction(t *testing.T) {
+	results := iout.NewInMemoryScoringResults(&iout.IdempotencyConfig{TTL: 7 * 24 * time.Hour, Size: 10})
+	router := newDedupingTestRouter(t, &stubHistory{}, results)
+	// Scored three days ago, past TRANSACTION_MAX_AGE by now
+	body := strings.Replace(scoreRequestBody(time.Now().Add(-72*time.Hour)), `"status": "completed"`, `"status": "completed", "idempotencyKey": "payment-key"`, 1)
+	req := &scoreTransactionRequest{}
+	if err := json.Unmarshal([]byte(body), req); err != nil {
+		t.Fatalf("Failed to decode request: %v", err)
+	}
+	card := &domain.ScoringResult{Transaction: *req.Transaction, Decision: domain.DecisionReview}
+	if err := results.Put(context.Background(), req.Transaction.IdempotencyKey(), card); err != nil {
+		t.Fatalf("Failed to keep the scorecard: %v", err)
+	}
+
+	rec := serve(router, http.MethodPost, "/v1/score/transaction", body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected the stored scorecard, got %d: %s", rec.Code, rec.Body)
+	}
+	var res scoringResponse
+	if err := json.NewDecoder(rec.Body).Decode(&res); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if res.Decision != domain.DecisionReview {
+		t.Errorf("Expected the decision of the stored scorecard, got %s", res.Decision)
+	}
+
+	// A transaction that old, never scored, still breaks the age rule
+	fresh := strings.Replace(body, `"payment-key"`, `"other-key"`, 1)
+	if rec := serve(router, http.MethodPost, "/v1/score/transaction", fresh); decodeError(t, rec).Code != codeValidationFailed {
+		t.Errorf("Expected %s for an old transaction never scored, got %d", codeValidationFailed, rec.Code)
+	}
+}
+
+func TestRouter_UnknownPath(t *testing.T) {
+	rec := serve(newTestRouter(t, &stubHistory{}), http.MethodGet, "/v1/unknown", "")
+	if rec.Code != http.StatusNotFound || decodeError(t, rec).Code != codeNotFound {
+		t.Errorf("Expected documented 404 error, got %d", rec.Code)
+	}
+}
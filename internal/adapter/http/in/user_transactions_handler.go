@@ -0,0 +1,132 @@
+package in
+
+import (
+	"context"
+	stderrors "errors"
+	"fraud-scoring/internal/domain/history"
+	"fraud-scoring/internal/domain/repositories"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const usersPath = basePath + "/users/"
+
+var monthPattern = regexp.MustCompile(`^\d{4}-\d{2}$`)
+
+// userMonthlyAverageResponse leaves out the total and the number of transactions when the provider that answered
+// doesn't know them.
+type userMonthlyAverageResponse struct {
+	Document         string             `json:"document"`
+	Month            string             `json:"month"`
+	Total            string             `json:"total,omitempty"`
+	TransactionCount int                `json:"transactionCount,omitempty"`
+	Average          string             `json:"average"`
+	Provenance       history.Provenance `json:"provenance"`
+}
+
+// userTransactionResponse leaves out the timestamp when the provider that answered doesn't know it.
+type userTransactionResponse struct {
+	Document   string             `json:"document"`
+	SellerId   string             `json:"sellerId"`
+	Currency   string             `json:"currency"`
+	Value      string             `json:"value"`
+	Timestamp  *time.Time         `json:"timestamp,omitempty"`
+	Provenance history.Provenance `json:"provenance"`
+}
+
+// UserTransactionsHandler serves the buyer history used by the scoring, under /users/{document}/transactions. utr must
+// not make up a history, so that a buyer without one is answered 404. Without utr, no provider can look a history up
+// and every buyer is answered 404.
+type UserTransactionsHandler struct {
+	utr repositories.UserTransactionsRepository
+	log *zap.Logger
+}
+
+func (uth *UserTransactionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, usersPath), "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] != "transactions" {
+		writeError(w, http.StatusNotFound, codeNotFound, "no route for "+r.URL.Path, nil)
+		return
+	}
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	if uth.utr == nil {
+		writeError(w, http.StatusNotFound, codeNotFound, "no history provider serves the user transactions", nil)
+		return
+	}
+	switch document := parts[0]; parts[2] {
+	case "average":
+		uth.average(w, r, document)
+	case "last":
+		uth.last(w, r, document)
+	default:
+		writeError(w, http.StatusNotFound, codeNotFound, "no route for "+r.URL.Path, nil)
+	}
+}
+
+func (uth *UserTransactionsHandler) average(w http.ResponseWriter, r *http.Request, document string) {
+	month := r.URL.Query().Get("month")
+	at, err := time.Parse(history.MonthLayout, month)
+	if !monthPattern.MatchString(month) || err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, "month must be a valid month in YYYY-MM format",
+			map[string]any{"field": "month", "value": month})
+		return
+	}
+	avg, err := uth.utr.AverageTransactions(r.Context(), document, at)
+	if err != nil {
+		uth.writeHistoryError(w, err, document)
+		return
+	}
+	writeJSON(w, http.StatusOK, userMonthlyAverageResponse{
+		Document:         document,
+		Month:            avg.Month,
+		Total:            avg.Total,
+		TransactionCount: avg.Count,
+		Average:          avg.Amount,
+		Provenance:       avg.Provenance,
+	})
+}
+
+func (uth *UserTransactionsHandler) last(w http.ResponseWriter, r *http.Request, document string) {
+	lo, err := uth.utr.LastOrder(r.Context(), document)
+	if err != nil {
+		uth.writeHistoryError(w, err, document)
+		return
+	}
+	res := userTransactionResponse{
+		Document:   document,
+		SellerId:   lo.SellerId,
+		Currency:   lo.Currency,
+		Value:      lo.Amount,
+		Provenance: lo.Provenance,
+	}
+	if !lo.At.IsZero() {
+		res.Timestamp = &lo.At
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// writeHistoryError answers 404 when the buyer has no history and 5xx when it could not be retrieved.
+func (uth *UserTransactionsHandler) writeHistoryError(w http.ResponseWriter, err error, document string) {
+	if stderrors.As(err, &repositories.HistoryNotFound{}) {
+		writeError(w, http.StatusNotFound, codeNotFound, "no history for user", map[string]any{"document": document})
+		return
+	}
+	uth.log.Error("error to retrieve user history", zap.String("user_id", document), zap.Error(err))
+	if stderrors.Is(err, context.DeadlineExceeded) || status.Code(err) == codes.DeadlineExceeded {
+		writeError(w, http.StatusGatewayTimeout, codeHistoryTimeout, "timed out retrieving user history", nil)
+		return
+	}
+	writeError(w, http.StatusServiceUnavailable, codeHistoryUnavailable, "user history is unavailable", nil)
+}
+
+func NewUserTransactionsHandler(utr repositories.UserTransactionsRepository, log *zap.Logger) *UserTransactionsHandler {
+	return &UserTransactionsHandler{utr: utr, log: log}
+}
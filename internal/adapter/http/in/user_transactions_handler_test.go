@@ -0,0 +1,120 @@
+package in
+
+import (
+	"context"
+	"encoding/json"
+	"fraud-scoring/internal/domain/repositories"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUserTransactionsHandler_Average(t *testing.T) {
+	rec := serve(newTestRouter(t, &stubHistory{}), http.MethodGet, "/v1/users/12345678901/transactions/average?month=2024-01", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var res userMonthlyAverageResponse
+	if err := json.NewDecoder(rec.Body).Decode(&res); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if res.Document != "12345678901" || res.Month != "2024-01" || res.Average != "100.00" || res.Total != "1500.00" || res.TransactionCount != 15 {
+		t.Errorf("Unexpected response %+v", res)
+	}
+}
+
+func TestUserTransactionsHandler_Last(t *testing.T) {
+	rec := serve(newTestRouter(t, &stubHistory{}), http.MethodGet, "/v1/users/12345678901/transactions/last", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var res userTransactionResponse
+	if err := json.NewDecoder(rec.Body).Decode(&res); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if res.SellerId != "seller-123" || res.Value != "100.00" || res.Timestamp == nil || res.Timestamp.Format(time.RFC3339) != "2024-01-15T10:30:00Z" {
+		t.Errorf("Unexpected response %+v", res)
+	}
+}
+
+func TestUserTransactionsHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		path           string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "Missing month",
+			path:           "/v1/users/12345678901/transactions/average",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   codeInvalidRequest,
+		},
+		{
+			name:           "Month in the wrong format",
+			path:           "/v1/users/12345678901/transactions/average?month=01-2024",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   codeInvalidRequest,
+		},
+		{
+			name:           "Month out of range",
+			path:           "/v1/users/12345678901/transactions/average?month=2024-13",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   codeInvalidRequest,
+		},
+		{
+			name:           "No history",
+			err:            repositories.HistoryNotFound{Err: status.Error(codes.NotFound, "user not found")},
+			path:           "/v1/users/12345678901/transactions/last",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   codeNotFound,
+		},
+		{
+			name:           "History unavailable",
+			err:            status.Error(codes.Unavailable, "connection refused"),
+			path:           "/v1/users/12345678901/transactions/last",
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedCode:   codeHistoryUnavailable,
+		},
+		{
+			name:           "History timed out",
+			err:            context.DeadlineExceeded,
+			path:           "/v1/users/12345678901/transactions/average?month=2024-01",
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedCode:   codeHistoryTimeout,
+		},
+		{
+			name:           "Unknown route",
+			path:           "/v1/users/12345678901/orders/last",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   codeNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(newTestRouter(t, &stubHistory{err: tt.err}), http.MethodGet, tt.path, "")
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if res := decodeError(t, rec); res.Code != tt.expectedCode {
+				t.Errorf("Expected code %s, got %s", tt.expectedCode, res.Code)
+			}
+		})
+	}
+}
+
+func TestUserTransactionsHandler_WithoutProvider(t *testing.T) {
+	uth := NewUserTransactionsHandler(nil, zaptest.NewLogger(t))
+	for _, path := range []string{"/v1/users/12345678901/transactions/last", "/v1/users/12345678901/transactions/average?month=2024-01"} {
+		rec := serve(uth, http.MethodGet, path, "")
+		if rec.Code != http.StatusNotFound || decodeError(t, rec).Code != codeNotFound {
+			t.Errorf("Expected 404 on %s, got %d: %s", path, rec.Code, rec.Body)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package out
+
+import (
+	"fraud-scoring/internal/infra/env"
+	"time"
+)
+
+type IdempotencyConfig struct {
+	// TTL is how long a scorecard is answered to the transactions delivered again with its idempotency key.
+	TTL time.Duration
+	// Size bounds the number of scorecards kept in memory.
+	Size int
+}
+
+func NewIdempotencyConfig() *IdempotencyConfig {
+	return &IdempotencyConfig{
+		TTL:  env.Duration("IDEMPOTENCY_TTL", 24*time.Hour),
+		Size: env.Int("IDEMPOTENCY_STORE_SIZE", 100000),
+	}
+}
@@ -0,0 +1,65 @@
+package out
+
+import (
+	"context"
+	"fraud-scoring/internal/domain"
+	"fraud-scoring/internal/domain/repositories"
+	"fraud-scoring/internal/infra/lru"
+	"sync"
+	"time"
+)
+
+// InMemoryScoringResults keeps the scorecards stored by this instance for the configured TTL. A transaction delivered
+// again to another instance, or after a restart, is scored again.
+type InMemoryScoringResults struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	now   func() time.Time
+	cards *lru.Cache[string, storedCard]
+}
+
+// storedCard is a claimed key, card is nil until the transaction holding it is scored.
+type storedCard struct {
+	fingerprint string
+	card        *domain.ScoringResult
+	at          time.Time
+}
+
+func (imsr *InMemoryScoringResults) Claim(_ context.Context, key, fingerprint string) (*repositories.ScoredTransaction, bool, error) {
+	imsr.mu.Lock()
+	defer imsr.mu.Unlock()
+	stored, ok := imsr.cards.Get(key)
+	if ok && imsr.now().Sub(stored.at) <= imsr.ttl {
+		held := &repositories.ScoredTransaction{Fingerprint: stored.fingerprint}
+		if stored.card != nil {
+			card := *stored.card
+			held.Card = &card
+		}
+		return held, false, nil
+	}
+	imsr.cards.Put(key, storedCard{fingerprint: fingerprint, at: imsr.now()})
+	return nil, true, nil
+}
+
+func (imsr *InMemoryScoringResults) Put(_ context.Context, key string, card *domain.ScoringResult) error {
+	imsr.mu.Lock()
+	defer imsr.mu.Unlock()
+	stored := *card
+	imsr.cards.Put(key, storedCard{fingerprint: card.Transaction.Fingerprint(), card: &stored, at: imsr.now()})
+	return nil
+}
+
+func (imsr *InMemoryScoringResults) Release(_ context.Context, key string) error {
+	imsr.mu.Lock()
+	defer imsr.mu.Unlock()
+	imsr.cards.Delete(key)
+	return nil
+}
+
+func NewInMemoryScoringResults(config *IdempotencyConfig) *InMemoryScoringResults {
+	return &InMemoryScoringResults{
+		ttl:   config.TTL,
+		now:   time.Now,
+		cards: lru.New[string, storedCard](config.Size),
+	}
+}
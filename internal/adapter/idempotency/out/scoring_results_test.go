@@ -0,0 +1,66 @@
+package out
+
+import (
+	"context"
+	"fraud-scoring/internal/domain"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestInMemoryScoringResults_ForgetsExpiredCards(t *testing.T) {
+	results := NewInMemoryScoringResults(&IdempotencyConfig{TTL: time.Hour, Size: 10})
+	ctx := context.Background()
+	card := &domain.ScoringResult{Decision: domain.DecisionApprove}
+	card.Transaction.Payment.Id = "pay-1"
+
+	fingerprint := card.Transaction.Fingerprint()
+	if _, claimed, err := results.Claim(ctx, "key", fingerprint); !claimed || err != nil {
+		t.Fatalf("Expected the key to be claimed, got %v, %v", claimed, err)
+	}
+	if err := results.Put(ctx, "key", card); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	held, claimed, err := results.Claim(ctx, "key", fingerprint)
+	if claimed || err != nil || held.Card == nil || held.Card.Transaction.Payment.Id != "pay-1" || held.Fingerprint != fingerprint {
+		t.Fatalf("Expected the stored card, got %+v, %v, %v", held, claimed, err)
+	}
+
+	results.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if _, claimed, _ := results.Claim(ctx, "key", fingerprint); !claimed {
+		t.Error("Expected an expired card to be forgotten")
+	}
+}
+
+func TestInMemoryScoringResults_ClaimsKeyOnce(t *testing.T) {
+	results := NewInMemoryScoringResults(&IdempotencyConfig{TTL: time.Hour, Size: 10})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	var claims atomic.Int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, claimed, _ := results.Claim(ctx, "key", "fingerprint"); claimed {
+				claims.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if claims.Load() != 1 {
+		t.Fatalf("Expected the key to be claimed once, got %d", claims.Load())
+	}
+
+	held, claimed, _ := results.Claim(ctx, "key", "fingerprint")
+	if claimed || held.Card != nil {
+		t.Errorf("Expected the key to be held while being scored, got %+v", held)
+	}
+	if err := results.Release(ctx, "key"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, claimed, _ := results.Claim(ctx, "key", "fingerprint"); !claimed {
+		t.Error("Expected a released key to be claimed again")
+	}
+}
@@ -2,65 +2,206 @@ package in
 
 import (
 	"context"
+	stderrors "errors"
 	"fraud-scoring/internal/domain"
 	"fraud-scoring/internal/domain/application"
+	"fraud-scoring/internal/domain/application/errors"
+	"fraud-scoring/internal/infra/kafka"
+	"fraud-scoring/internal/infra/tracing"
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"time"
 )
 
-const customDateFormat = "2006-01-02T15:04:05.000000"
-const eventType = "funny-bunny.xyz.payment-processing.v1.payment.created"
+const (
+	outcomeScored   = "scored"
+	outcomeRejected = "rejected"
+	outcomeIgnored  = "ignored"
+	outcomeInvalid  = "invalid"
+	outcomeFailed   = "failed"
+)
+
+// Error classes of the events sent to the retry and dead-letter topics.
+const (
+	classInvalidData        = "invalid_data"
+	classSchemaViolation    = "schema_violation"
+	classInvalidDate        = "invalid_date"
+	classScoringTimeout     = "scoring_timeout"
+	classHistoryUnavailable = "history_unavailable"
+	classScoringFailed      = "scoring_failed"
+	classRejectionFailed    = "rejection_failed"
+	classKeyReused          = "idempotency_key_reused"
+	classScoringInProgress  = "scoring_in_progress"
+)
+
+var eventsConsumed = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "fraud_scoring_events_consumed_total",
+	Help: "Events read from the payment processing topic per type and outcome, types without contract are counted as other.",
+}, []string{"type", "outcome"})
+
+var checkoutTimeSources = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "fraud_scoring_checkout_time_source_total",
+	Help: "Transactions read from events per source of their checkout time, the checkout or the time of the event.",
+}, []string{"source"})
+
+var tracer = otel.Tracer("fraud-scoring/internal/adapter/kafka/in")
 
 type CheckoutEventReceiver struct {
-	scr *application.PaymentRiskScoring
-	log *zap.Logger
+	scr      *application.PaymentRiskScoring
+	tv       *application.TransactionValidation
+	registry *EventRegistry
+	times    *domain.CheckoutTimeParser
+	log      *zap.Logger
 }
 
+// Handle scores the payments of the events with a contract in the registry, continuing the trace of the event when it
+// carries one. Transactions failing the validation are rejected rather than scored, unless they were already scored.
 func (cer *CheckoutEventReceiver) Handle(ctx context.Context, event cloudevents.Event) error {
-	if eventType == event.Type() {
-		data := &domain.CheckoutData{}
-		if err := event.DataAs(data); err != nil {
-			cer.log.Error("error to retrieve deserialize cloud event data", zap.String("error", err.Error()))
-			return err
+	ctx, span := tracer.Start(tracing.Extract(ctx, &event), "CheckoutEventReceiver.Handle",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationReceive,
+			semconv.CloudeventsEventID(event.ID()),
+			semconv.CloudeventsEventType(event.Type()),
+			semconv.CloudeventsEventSource(event.Source()),
+		))
+	defer span.End()
+	err := cer.handle(ctx, event)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+func (cer *CheckoutEventReceiver) handle(ctx context.Context, event cloudevents.Event) error {
+	contract, ok := cer.registry.Lookup(event.Type())
+	if !ok {
+		cer.unknown(event)
+		return nil
+	}
+	eventType := contract.Type
+	data, err := contract.Decode(event)
+	if err != nil {
+		eventsConsumed.WithLabelValues(eventType, outcomeInvalid).Inc()
+		cer.log.Error("error to retrieve deserialize cloud event data",
+			zap.String("type", eventType),
+			zap.String("error", err.Error()))
+		var violation SchemaViolation
+		if stderrors.As(err, &violation) {
+			return kafka.HandlerError{Class: classSchemaViolation, Err: err, Details: violation.Fields}
 		}
-		t, err := time.Parse(customDateFormat, data.Checkout.At)
+		return kafka.HandlerError{Class: classInvalidData, Err: err}
+	}
+	analysis, err := data.TransactionAnalysis(cer.times, event.Time())
+	if err != nil {
+		eventsConsumed.WithLabelValues(eventType, outcomeInvalid).Inc()
+		cer.log.Error("error to parse date for transaction", zap.String("id", data.Payment.Id), zap.Error(err))
+		return kafka.HandlerError{Class: classInvalidDate, Err: err}
+	}
+	checkoutTimeSources.WithLabelValues(analysis.Order.AtSource).Inc()
+	if analysis.Order.AtSource == domain.CheckoutTimeFromEvent {
+		cer.log.Warn("checkout time missing or unreadable, using the time of the event",
+			zap.String("id", data.Payment.Id),
+			zap.String("at", data.Checkout.At))
+	}
+	_, err = cer.scr.VettedAssessment(ctx, analysis, cer.vet(receivedAt(event, analysis)))
+	if stderrors.As(err, &errors.TransactionRejected{}) {
+		eventsConsumed.WithLabelValues(eventType, outcomeRejected).Inc()
+		return nil
+	}
+	if err != nil {
+		eventsConsumed.WithLabelValues(eventType, outcomeFailed).Inc()
+		cer.log.Error("error to make scorecard for transaction", zap.String("id", analysis.Payment.Id))
+		return scoringError(err)
+	}
+	eventsConsumed.WithLabelValues(eventType, outcomeScored).Inc()
+	return nil
+}
+
+// vet validates the transactions once they are known not to be duplicates, so a transaction already scored gets its
+// scorecard again however old it is now. The age of the transaction is measured at receivedAt.
+func (cer *CheckoutEventReceiver) vet(receivedAt time.Time) application.Vet {
+	return func(ctx context.Context, transaction *domain.TransactionAnalysis) error {
+		validation, err := cer.tv.Validate(ctx, transaction, receivedAt)
 		if err != nil {
-			cer.log.Error("error to parse date for transaction", zap.String("id", data.Payment.Id))
 			return err
 		}
-		analysis := &domain.TransactionAnalysis{
-			Participants: domain.Participants{
-				Buyer: domain.BuyerInfo{
-					Document: data.Checkout.BuyerInfo.Document,
-					Name:     data.Checkout.BuyerInfo.Name,
-				},
-				Seller: domain.SellerInfo{SellerId: data.Payment.SellerInfo.SellerId},
-			},
-			Order: domain.Checkout{
-				Id: data.Checkout.Id,
-				PaymentType: domain.CardInfo{
-					CardInfo: data.Checkout.CardInfo.CardInfo,
-					Token:    data.Checkout.CardInfo.Token,
-				},
-				At: t,
-			},
-			Payment: domain.Payment{
-				Amount:   data.Payment.Amount,
-				Currency: data.Payment.Currency,
-				Status:   data.Payment.Status,
-				Id:       data.Payment.Id,
-			},
-		}
-		err = cer.scr.Assessment(analysis)
-		if err != nil {
-			cer.log.Error("error to make scorecard for transaction", zap.String("id", analysis.Payment.Id))
-			return err
+		if !validation.IsValid {
+			return errors.TransactionRejected{Errors: validation.Errors}
 		}
+		return nil
+	}
+}
+
+// receivedAt is when the payment of event was published, its CloudEvent time, which the retry and dead-letter topics
+// keep: a backlog, a retry or a redrive is judged as it would have been on time. An event without time is judged at
+// its checkout time, the age rule doesn't apply to it.
+func receivedAt(event cloudevents.Event, analysis *domain.TransactionAnalysis) time.Time {
+	if at := event.Time(); !at.IsZero() {
+		return at
+	}
+	return analysis.Order.At
+}
+
+// unknown counts an event no contract reads. The payment processing ones are likely of a version not supported yet
+// and are logged as such, the others are not meant for the scoring.
+func (cer *CheckoutEventReceiver) unknown(event cloudevents.Event) {
+	eventsConsumed.WithLabelValues("other", outcomeIgnored).Inc()
+	label := unknownType(event.Type())
+	unknownEvents.WithLabelValues(label).Inc()
+	if label != "other" {
+		cer.log.Warn("ignoring payment processing event of unsupported type",
+			zap.String("type", event.Type()),
+			zap.String("source", event.Source()),
+			zap.String("id", event.ID()))
+	}
+}
+
+// OrderingKey keys the checkout events by buyer, so the transactions of a buyer are scored in the order they were read,
+// each with the history of the ones before.
+func (cer *CheckoutEventReceiver) OrderingKey(event cloudevents.Event) string {
+	contract, ok := cer.registry.Lookup(event.Type())
+	if !ok {
+		return ""
+	}
+	// The data is validated when the event is handled, an invalid one is dead-lettered whatever its key.
+	data, err := contract.decode(event)
+	if err != nil {
+		return ""
+	}
+	return data.Checkout.BuyerInfo.Document
+}
+
+// scoringError classifies why an assessment failed. A reused idempotency key is terminal, the other failures come from the user transactions service, the budget, the publishing of the scorecard or of the
+// rejection, or a delivery of the same transaction still being scored, and are worth retrying.
+func scoringError(err error) kafka.HandlerError {
+	var budget errors.ScoringBudgetExceeded
+	var lastOrder errors.LastOrderNotFound
+	var average errors.AverageTransactionsNotFound
+	switch {
+	case stderrors.As(err, &errors.RejectionFailed{}):
+		return kafka.HandlerError{Class: classRejectionFailed, Retryable: true, Err: err}
+	case stderrors.Is(err, errors.ErrIdempotencyKeyReused):
+		return kafka.HandlerError{Class: classKeyReused, Err: err}
+	case stderrors.Is(err, errors.ErrScoringInProgress):
+		return kafka.HandlerError{Class: classScoringInProgress, Retryable: true, Err: err}
+	case stderrors.As(err, &budget):
+		return kafka.HandlerError{Class: classScoringTimeout, Retryable: true, Err: err}
+	case stderrors.As(err, &lastOrder), stderrors.As(err, &average):
+		return kafka.HandlerError{Class: classHistoryUnavailable, Retryable: true, Err: err}
+	default:
+		return kafka.HandlerError{Class: classScoringFailed, Retryable: true, Err: err}
 	}
-	return nil
 }
 
-func NewCheckoutEventReceiver(scr *application.PaymentRiskScoring, log *zap.Logger) *CheckoutEventReceiver {
-	return &CheckoutEventReceiver{scr: scr, log: log}
+func NewCheckoutEventReceiver(scr *application.PaymentRiskScoring, tv *application.TransactionValidation, registry *EventRegistry, times *domain.CheckoutTimeParser, log *zap.Logger) *CheckoutEventReceiver {
+	return &CheckoutEventReceiver{scr: scr, tv: tv, registry: registry, times: times, log: log}
 }
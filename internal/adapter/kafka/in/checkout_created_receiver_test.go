@@ -0,0 +1,213 @@
+package in
+
+import (
+	"context"
+	stderrors "errors"
+	iout "fraud-scoring/internal/adapter/idempotency/out"
+	"fraud-scoring/internal/domain"
+	"fraud-scoring/internal/domain/application"
+	"fraud-scoring/internal/domain/application/errors"
+	"fraud-scoring/internal/domain/history"
+	"fraud-scoring/internal/domain/repositories"
+	"fraud-scoring/internal/infra/kafka"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestScoringError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		class     string
+		retryable bool
+	}{
+		{
+			name:      "User transactions service unavailable",
+			err:       errors.LastOrderNotFound{Err: stderrors.New("connection refused")},
+			class:     classHistoryUnavailable,
+			retryable: true,
+		},
+		{
+			name:      "Budget exceeded",
+			err:       errors.ScoringBudgetExceeded{Err: context.DeadlineExceeded},
+			class:     classScoringTimeout,
+			retryable: true,
+		},
+		{
+			name:  "Idempotency key reused",
+			err:   errors.IdempotencyConflict{Key: "checkout-key/", Err: errors.ErrIdempotencyKeyReused},
+			class: classKeyReused,
+		},
+		{
+			name:      "Same transaction being scored",
+			err:       errors.IdempotencyConflict{Key: "checkout-key/", Err: errors.ErrScoringInProgress},
+			class:     classScoringInProgress,
+			retryable: true,
+		},
+		{
+			name:      "Rejection not published",
+			err:       errors.RejectionFailed{Err: stderrors.New("kafka: client has run out of available brokers")},
+			class:     classRejectionFailed,
+			retryable: true,
+		},
+		{
+			name:      "Scorecard not published",
+			err:       stderrors.New("kafka: client has run out of available brokers"),
+			class:     classScoringFailed,
+			retryable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			he := scoringError(tt.err)
+			if he.Class != tt.class || he.Retryable != tt.retryable {
+				t.Errorf("Expected %s retryable=%v, got %s retryable=%v", tt.class, tt.retryable, he.Class, he.Retryable)
+			}
+			if !stderrors.Is(he, tt.err) {
+				t.Error("Expected the handler error to wrap the assessment error")
+			}
+		})
+	}
+}
+
+func TestCheckoutEventReceiver_OrderingKey(t *testing.T) {
+	cer := &CheckoutEventReceiver{registry: newTestRegistry(t)}
+	event := cloudevents.NewEvent()
+	event.SetType(paymentCreatedV1)
+	_ = event.SetData(cloudevents.ApplicationJSON, map[string]any{
+		"checkout": map[string]any{"buyerInfo": map[string]any{"document": "12345678901"}},
+	})
+	if key := cer.OrderingKey(event); key != "12345678901" {
+		t.Errorf("Expected the buyer document as key, got %q", key)
+	}
+
+	event.SetType("other")
+	if key := cer.OrderingKey(event); key != "" {
+		t.Errorf("Expected no key for other events, got %q", key)
+	}
+}
+
+func TestCheckoutEventReceiver_RejectsEventsWithoutTime(t *testing.T) {
+	cer := &CheckoutEventReceiver{registry: newTestRegistry(t), times: domain.NewCheckoutTimeParser(nil, nil), log: zaptest.NewLogger(t)}
+	// Neither the checkout nor the event tell when the transaction happened
+	event := newPaymentEvent(t, paymentCreatedV1, map[string]any{
+		"checkout": map[string]any{"id": "checkout-1", "buyerInfo": map[string]any{"document": "12345678901"}},
+		"payment": map[string]any{
+			"id":         "payment-1",
+			"amount":     "100.00",
+			"currency":   "BRL",
+			"sellerInfo": map[string]any{"sellerId": "seller-1"},
+		},
+	})
+
+	var he kafka.HandlerError
+	if err := cer.handle(context.Background(), event); !stderrors.As(err, &he) || he.Class != classInvalidDate {
+		t.Fatalf("Expected an %s handler error, got %v", classInvalidDate, err)
+	}
+}
+
+type stubRejections struct {
+	stored []*domain.TransactionRejection
+}
+
+func (s *stubRejections) Store(_ context.Context, rejection *domain.TransactionRejection) error {
+	s.stored = append(s.stored, rejection)
+	return nil
+}
+
+// noHistory answers every buyer as a first purchase.
+type noHistory struct{}
+
+func (noHistory) LastOrder(context.Context, string) (*history.LastOrder, error) {
+	return nil, repositories.HistoryNotFound{Err: stderrors.New("no history")}
+}
+
+func (noHistory) AverageTransactions(context.Context, string, time.Time) (*history.AveragePayment, error) {
+	return nil, repositories.HistoryNotFound{Err: stderrors.New("no history")}
+}
+
+func (noHistory) AverageBaseline(context.Context, string, time.Time, int) (*history.AverageBaseline, error) {
+	return nil, repositories.HistoryNotFound{Err: stderrors.New("no history")}
+}
+
+type stubScoreCards struct {
+	stored []*domain.ScoringResult
+}
+
+func (s *stubScoreCards) Store(_ context.Context, card *domain.ScoringResult) error {
+	s.stored = append(s.stored, card)
+	return nil
+}
+
+func newTestReceiver(t *testing.T, cards *stubScoreCards, rejections *stubRejections) *CheckoutEventReceiver {
+	log := zaptest.NewLogger(t)
+	config := &application.ScoringConfig{
+		Budget:         time.Second,
+		BaselineMonths: 3,
+		Decision:       domain.DecisionPolicy{ReviewAt: -3, DeclineAt: -6},
+	}
+	results := iout.NewInMemoryScoringResults(&iout.IdempotencyConfig{TTL: time.Hour, Size: 10})
+	scr := application.NewPaymentRiskScoring(noHistory{}, cards, results, config, log)
+	tc := domain.NewTransactionComponent(24*time.Hour, 0.01, 1000000, []string{"BRL"})
+	return NewCheckoutEventReceiver(scr, application.NewTransactionValidation(tc, rejections, log), newTestRegistry(t), domain.NewCheckoutTimeParser(nil, nil), log)
+}
+
+func newCheckoutEvent(t *testing.T, currency string, at time.Time) cloudevents.Event {
+	event := newPaymentEvent(t, paymentCreatedV1, map[string]any{
+		"checkout": map[string]any{
+			"id":             "checkout-1",
+			"buyerInfo":      map[string]any{"document": "12345678901", "name": "John Doe"},
+			"cardInfo":       map[string]any{"cardInfo": "****1234", "token": "tok_1"},
+			"idempotencyKey": "checkout-key",
+			"at":             at.UTC().Format(time.RFC3339),
+		},
+		"payment": map[string]any{
+			"id":             "payment-1",
+			"amount":         "100.00",
+			"currency":       currency,
+			"status":         "completed",
+			"sellerInfo":     map[string]any{"sellerId": "seller-1"},
+			"idempotencyKey": "payment-key",
+		},
+	})
+	event.SetTime(at)
+	return event
+}
+
+func TestCheckoutEventReceiver_RejectsInvalidTransactions(t *testing.T) {
+	cards, rejections := &stubScoreCards{}, &stubRejections{}
+	cer := newTestReceiver(t, cards, rejections)
+
+	if err := cer.handle(context.Background(), newCheckoutEvent(t, "USD", time.Now())); err != nil {
+		t.Fatalf("Expected the event to be consumed, got %v", err)
+	}
+	if len(rejections.stored) != 1 || len(rejections.stored[0].Errors) != 1 || rejections.stored[0].Errors[0] != "unsupported currency" {
+		t.Errorf("Expected the transaction rejected for its currency, got %+v", rejections.stored)
+	}
+	if len(cards.stored) != 0 {
+		t.Errorf("Expected a rejected transaction not to be scored, got %d scorecards", len(cards.stored))
+	}
+}
+
+func TestCheckoutEventReceiver_ScoresRedrivenEvents(t *testing.T) {
+	cards, rejections := &stubScoreCards{}, &stubRejections{}
+	cer := newTestReceiver(t, cards, rejections)
+	// Published three days ago, past TRANSACTION_MAX_AGE by now, and redriven from the dead-letter topic twice
+	event := newCheckoutEvent(t, "BRL", time.Now().Add(-72*time.Hour))
+
+	for i := 0; i < 2; i++ {
+		if err := cer.handle(context.Background(), event); err != nil {
+			t.Fatalf("Expected the redriven event to be consumed, got %v", err)
+		}
+	}
+	if len(rejections.stored) != 0 {
+		t.Errorf("Expected the event to be judged at its publication, got rejected for %v", rejections.stored[0].Errors)
+	}
+	if len(cards.stored) != 1 {
+		t.Errorf("Expected the event to be scored once, got %d scorecards", len(cards.stored))
+	}
+}
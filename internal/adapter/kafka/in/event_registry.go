@@ -0,0 +1,92 @@
+package in
+
+import (
+	"fraud-scoring/internal/domain"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// paymentCreatedV1 is the type of the payment created events, the only version published so far.
+const paymentCreatedV1 = "funny-bunny.xyz.payment-processing.v1.payment.created"
+
+// paymentProcessingTypes prefixes the types of the payment processing events, the unknown ones are counted by type.
+const paymentProcessingTypes = "funny-bunny.xyz.payment-processing."
+
+var unknownEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "fraud_scoring_events_unknown_total",
+	Help: "Events of a type no contract reads, per type for payment processing events and other for the rest.",
+}, []string{"type"})
+
+// EventContract reads the events of one type: it validates their data against the JSON Schema of its version, decodes
+// it and upcasts it to the checkout data the scoring reads.
+type EventContract struct {
+	Type    string
+	Version string
+	schema  *jsonschema.Schema
+	decode  func(event cloudevents.Event) (*domain.CheckoutData, error)
+}
+
+// Decode reads the checkout data of event. Data breaking the schema is rejected with a SchemaViolation, rather than
+// being read as zero values.
+func (ec EventContract) Decode(event cloudevents.Event) (*domain.CheckoutData, error) {
+	if err := validate(ec.schema, event.Data()); err != nil {
+		return nil, err
+	}
+	return ec.decode(event)
+}
+
+// newContract reads the data of the events of eventType as a T, once validated against the embedded schema file, then
+// upcasts it.
+func newContract[T any](eventType, version, schema string, upcast func(*T) *domain.CheckoutData) (EventContract, error) {
+	compiled, err := compileSchema(schema)
+	if err != nil {
+		return EventContract{}, err
+	}
+	return EventContract{Type: eventType, Version: version, schema: compiled, decode: func(event cloudevents.Event) (*domain.CheckoutData, error) {
+		data := new(T)
+		if err := event.DataAs(data); err != nil {
+			return nil, err
+		}
+		return upcast(data), nil
+	}}, nil
+}
+
+// EventRegistry holds the contracts of the events the receiver scores, so versions of the payment events can be
+// published side by side while producers migrate.
+type EventRegistry struct {
+	contracts map[string]EventContract
+}
+
+// Lookup returns the contract of the events of eventType, false when none reads them.
+func (er *EventRegistry) Lookup(eventType string) (EventContract, bool) {
+	contract, ok := er.contracts[eventType]
+	return contract, ok
+}
+
+// unknownType is the label of an event type without contract, other outside of the payment processing events.
+func unknownType(eventType string) string {
+	if strings.HasPrefix(eventType, paymentProcessingTypes) {
+		return eventType
+	}
+	return "other"
+}
+
+func newEventRegistry(contracts ...EventContract) *EventRegistry {
+	er := &EventRegistry{contracts: map[string]EventContract{}}
+	for _, contract := range contracts {
+		er.contracts[contract.Type] = contract
+	}
+	return er
+}
+
+func NewEventRegistry() (*EventRegistry, error) {
+	v1, err := newContract(paymentCreatedV1, "v1", "payment.created.v1.json", func(data *domain.CheckoutData) *domain.CheckoutData { return data })
+	if err != nil {
+		return nil, err
+	}
+	return newEventRegistry(v1), nil
+}
@@ -0,0 +1,105 @@
+package in
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+func newTestRegistry(t *testing.T) *EventRegistry {
+	registry, err := NewEventRegistry()
+	if err != nil {
+		t.Fatalf("Failed to create the event registry: %v", err)
+	}
+	return registry
+}
+
+func newPaymentEvent(t *testing.T, eventType string, data map[string]any) cloudevents.Event {
+	event := cloudevents.NewEvent()
+	event.SetID("event-1")
+	event.SetSource("payment-gateway")
+	event.SetType(eventType)
+	if err := event.SetData(cloudevents.ApplicationJSON, data); err != nil {
+		t.Fatalf("Failed to set data: %v", err)
+	}
+	return event
+}
+
+func TestEventRegistry_DecodesV1(t *testing.T) {
+	registry := newTestRegistry(t)
+	event := newPaymentEvent(t, paymentCreatedV1, map[string]any{
+		"checkout": map[string]any{
+			"id":             "checkout-1",
+			"buyerInfo":      map[string]any{"document": "12345678901", "name": "John Doe"},
+			"cardInfo":       map[string]any{"cardInfo": "****1234", "token": "tok_1"},
+			"idempotencyKey": "checkout-key",
+			"at":             "2024-01-15T10:30:00.000000",
+		},
+		"payment": map[string]any{
+			"id":             "payment-1",
+			"amount":         "100.00",
+			"currency":       "BRL",
+			"status":         "completed",
+			"sellerInfo":     map[string]any{"sellerId": "seller-1"},
+			"idempotencyKey": "payment-key",
+		},
+	})
+
+	contract, ok := registry.Lookup(paymentCreatedV1)
+	if !ok {
+		t.Fatalf("Expected a contract for %s", paymentCreatedV1)
+	}
+	data, err := contract.Decode(event)
+	if err != nil {
+		t.Fatalf("Expected %s to decode, got %v", contract.Version, err)
+	}
+	if data.Checkout.BuyerInfo.Document != "12345678901" || data.Payment.Amount != "100.00" ||
+		data.Payment.SellerInfo.SellerId != "seller-1" || data.Payment.IdempotencyKey != "payment-key" {
+		t.Errorf("Expected the checkout of the event, got %+v", *data)
+	}
+}
+
+func TestEventRegistry_UnknownTypes(t *testing.T) {
+	registry := newTestRegistry(t)
+	if _, ok := registry.Lookup("funny-bunny.xyz.payment-processing.v2.payment.created"); ok {
+		t.Error("Expected no contract for an unsupported version")
+	}
+	if label := unknownType("funny-bunny.xyz.payment-processing.v2.payment.created"); label != "funny-bunny.xyz.payment-processing.v2.payment.created" {
+		t.Errorf("Expected payment processing types to be counted by type, got %s", label)
+	}
+	if label := unknownType("com.example.order.shipped"); label != "other" {
+		t.Errorf("Expected other types to be counted as other, got %s", label)
+	}
+}
+
+func TestEventContract_RejectsDataBreakingItsSchema(t *testing.T) {
+	contract, _ := newTestRegistry(t).Lookup(paymentCreatedV1)
+	event := newPaymentEvent(t, paymentCreatedV1, map[string]any{
+		"checkout": map[string]any{
+			"id":        "checkout-1",
+			"buyerInfo": map[string]any{"document": "12345678901"},
+			"at":        "2024-01-15T10:30:00.000000",
+		},
+		"payment": map[string]any{
+			"id":         "payment-1",
+			"amount":     "100,00",
+			"sellerInfo": map[string]any{"sellerId": ""},
+		},
+	})
+
+	_, err := contract.Decode(event)
+	var violation SchemaViolation
+	if !errors.As(err, &violation) {
+		t.Fatalf("Expected a schema violation, got %v", err)
+	}
+	var fields []string
+	for _, f := range violation.Fields {
+		fields = append(fields, f.Field)
+	}
+	expected := []string{"/payment", "/payment/amount", "/payment/sellerInfo/sellerId"}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("Expected violations of %v, got %+v", expected, violation.Fields)
+	}
+}
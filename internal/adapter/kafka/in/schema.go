@@ -0,0 +1,85 @@
+package in
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+//go:embed schemas/*.json
+var schemas embed.FS
+
+// FieldViolation is a field of the event data breaking its schema, Field being its JSON pointer.
+type FieldViolation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// SchemaViolation is returned for event data that doesn't match the schema of its contract.
+type SchemaViolation struct {
+	Schema string
+	Fields []FieldViolation
+}
+
+func (sv SchemaViolation) Error() string {
+	fields := make([]string, len(sv.Fields))
+	for i, f := range sv.Fields {
+		fields[i] = f.Field + ": " + f.Message
+	}
+	return fmt.Sprintf("data does not match schema %s: %s", sv.Schema, strings.Join(fields, "; "))
+}
+
+// compileSchema compiles the embedded schema file name.
+func compileSchema(name string) (*jsonschema.Schema, error) {
+	raw, err := schemas.ReadFile("schemas/" + name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema %s: %w", name, err)
+	}
+	schema, err := jsonschema.CompileString(name, string(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile schema %s: %w", name, err)
+	}
+	return schema, nil
+}
+
+// validate checks data against schema, reporting each field that breaks it.
+func validate(schema *jsonschema.Schema, data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return fmt.Errorf("data is not JSON: %w", err)
+	}
+	err := schema.Validate(v)
+	if err == nil {
+		return nil
+	}
+	ve, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return err
+	}
+	sv := SchemaViolation{Schema: strings.TrimSuffix(schema.Location, "#")}
+	collectViolations(ve, &sv.Fields)
+	sort.SliceStable(sv.Fields, func(i, j int) bool { return sv.Fields[i].Field < sv.Fields[j].Field })
+	return sv
+}
+
+// collectViolations adds the leaves of ve, the errors that point at a field rather than at the schemas they belong to.
+func collectViolations(ve *jsonschema.ValidationError, fields *[]FieldViolation) {
+	if len(ve.Causes) == 0 {
+		field := ve.InstanceLocation
+		if field == "" {
+			field = "/"
+		}
+		*fields = append(*fields, FieldViolation{Field: field, Message: ve.Message})
+		return
+	}
+	for _, cause := range ve.Causes {
+		collectViolations(cause, fields)
+	}
+}
@@ -0,0 +1,64 @@
+package out
+
+import (
+	"context"
+	"fraud-scoring/internal/domain"
+	"fraud-scoring/internal/infra/kafka"
+	"fraud-scoring/internal/infra/tracing"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	rejectionEventType    = "funny-bunny.xyz.fraud-detection.v1.transaction.rejected"
+	rejectionEventSubject = "transaction-rejected"
+)
+
+// rejectionNamespace derives the rejection event ids, apart from the scorecard ones.
+var rejectionNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://funny-bunny.xyz/fraud-scoring/rejection"))
+
+var rejectionsPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "fraud_scoring_rejections_published_total",
+	Help: "Transaction rejections sent to the fraud detection topic per result: ack, nack or undelivered.",
+}, []string{"result"})
+
+// KafkaTransactionRejections publishes the rejected transactions to the fraud detection topic, next to the score cards.
+type KafkaTransactionRejections struct {
+	cli kafka.CloudEventsSender
+	log *zap.Logger
+}
+
+// Store publishes rejection, with the trace context of ctx in the event. Its id derives from the transaction like the
+// one of a score card, so a rejection published again can be deduped.
+func (ktr *KafkaTransactionRejections) Store(ctx context.Context, rejection *domain.TransactionRejection) (err error) {
+	ctx, span := tracer.Start(ctx, "KafkaTransactionRejections.Store",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(semconv.MessagingSystemKafka, semconv.MessagingOperationPublish))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+	e := cloudevents.NewEvent()
+	e.SetID(transactionEventID(rejectionNamespace, &rejection.Transaction))
+	e.SetType(rejectionEventType)
+	e.SetSource(eventSource)
+	e.SetSubject(rejectionEventSubject)
+	e.SetExtension(eventAudienceName, eventAudienceData)
+	e.SetExtension(eventContextName, eventContextData)
+	_ = e.SetData(cloudevents.ApplicationJSON, rejection)
+	tracing.Inject(ctx, &e)
+	return send(ctx, ktr.cli, e, span, rejectionsPublished, ktr.log)
+}
+
+func NewKafkaTransactionRejections(cli kafka.CloudEventsSender, log *zap.Logger) *KafkaTransactionRejections {
+	return &KafkaTransactionRejections{cli: cli, log: log}
+}
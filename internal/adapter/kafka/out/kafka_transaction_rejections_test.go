@@ -0,0 +1,51 @@
+package out
+
+import (
+	"context"
+	"fraud-scoring/internal/domain"
+	"fraud-scoring/internal/infra/kafka"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap/zaptest"
+)
+
+// fakeSender records the events sent instead of publishing them.
+type fakeSender struct {
+	kafka.CloudEventsSender
+	sent []cloudevents.Event
+}
+
+func (fs *fakeSender) Send(_ context.Context, event cloudevents.Event) cloudevents.Result {
+	fs.sent = append(fs.sent, event)
+	return cloudevents.ResultACK
+}
+
+func TestKafkaTransactionRejections_Store(t *testing.T) {
+	sender := &fakeSender{}
+	ktr := NewKafkaTransactionRejections(sender, zaptest.NewLogger(t))
+	rejection := &domain.TransactionRejection{Errors: []string{"unsupported currency"}}
+	rejection.Transaction.Payment.Id = "pay-1"
+
+	if err := ktr.Store(context.Background(), rejection); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(sender.sent) != 1 {
+		t.Fatalf("Expected one event sent, got %d", len(sender.sent))
+	}
+	event := sender.sent[0]
+	if event.Type() != rejectionEventType {
+		t.Errorf("Expected a %s event, got %s", rejectionEventType, event.Type())
+	}
+	card := &domain.ScoringResult{Transaction: rejection.Transaction}
+	if event.ID() == eventID(card) {
+		t.Error("Expected the rejection id apart from the score card one")
+	}
+	data := &domain.TransactionRejection{}
+	if err := event.DataAs(data); err != nil {
+		t.Fatalf("Failed to read the event data: %v", err)
+	}
+	if len(data.Errors) != 1 || data.Errors[0] != "unsupported currency" || data.Transaction.Payment.Id != "pay-1" {
+		t.Errorf("Unexpected rejection %+v", data)
+	}
+}
@@ -4,10 +4,18 @@ import (
 	"context"
 	"fraud-scoring/internal/domain"
 	"fraud-scoring/internal/infra/kafka"
+	"fraud-scoring/internal/infra/tracing"
 	"github.com/IBM/sarama"
 	"github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -21,31 +29,85 @@ const (
 	eventAudienceName = "audience"
 )
 
+// eventNamespace derives the scorecard event ids, see eventID.
+var eventNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://funny-bunny.xyz/fraud-scoring/scorecard"))
+
+var tracer = otel.Tracer("fraud-scoring/internal/adapter/kafka/out")
+
+var published = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "fraud_scoring_scorecards_published_total",
+	Help: "Score cards sent to the fraud detection topic per result: ack, nack or undelivered.",
+}, []string{"result"})
+
 type KafkaTransactionScoreCard struct {
 	cli kafka.CloudEventsSender
 	log *zap.Logger
 }
 
-func (ktsc *KafkaTransactionScoreCard) Store(card *domain.ScoringResult) error {
+// Store publishes card to the fraud detection topic, with the trace context of ctx in the event.
+func (ktsc *KafkaTransactionScoreCard) Store(ctx context.Context, card *domain.ScoringResult) (err error) {
+	ctx, span := tracer.Start(ctx, "KafkaTransactionScoreCard.Store",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(semconv.MessagingSystemKafka, semconv.MessagingOperationPublish))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
 	e := cloudevents.NewEvent()
-	e.SetID(uuid.New().String())
+	e.SetID(eventID(card))
 	e.SetType(eventType)
 	e.SetSource(eventSource)
 	e.SetSubject(eventSubject)
 	e.SetExtension(eventAudienceName, eventAudienceData)
 	e.SetExtension(eventContextName, eventContextData)
 	_ = e.SetData(cloudevents.ApplicationJSON, card)
-	if result := ktsc.cli.Send(
-		kafka_sarama.WithMessageKey(context.Background(), sarama.StringEncoder(e.ID())),
+	tracing.Inject(ctx, &e)
+	return send(ctx, ktsc.cli, e, span, published, ktsc.log)
+}
+
+// send publishes e, keyed by its id, counting the result in results.
+func send(ctx context.Context, cli kafka.CloudEventsSender, e cloudevents.Event, span trace.Span, results *prometheus.CounterVec, log *zap.Logger) error {
+	span.SetAttributes(semconv.CloudeventsEventID(e.ID()), semconv.CloudeventsEventType(e.Type()))
+	if result := cli.Send(
+		kafka_sarama.WithMessageKey(ctx, sarama.StringEncoder(e.ID())),
 		e,
 	); cloudevents.IsUndelivered(result) {
-		ktsc.log.Error("failed to send", zap.String("error", result.Error()))
+		results.WithLabelValues("undelivered").Inc()
+		log.Error("failed to send", zap.String("type", e.Type()), zap.String("error", result.Error()))
+		return result
 	} else {
-		ktsc.log.Info("message sent", zap.String("id", e.ID()), zap.Bool("ack", cloudevents.IsACK(result)))
+		ack := cloudevents.IsACK(result)
+		span.SetAttributes(attribute.Bool("messaging.ack", ack))
+		if ack {
+			results.WithLabelValues("ack").Inc()
+		} else {
+			results.WithLabelValues("nack").Inc()
+		}
+		log.Info("message sent", zap.String("id", e.ID()), zap.String("type", e.Type()), zap.Bool("ack", ack))
 	}
 	return nil
 }
 
+// eventID is the same for every scorecard of a transaction, so consumers can dedupe the ones published again. It
+// derives from the idempotency key, or the payment id when there is none, and is random when there is neither.
+func eventID(card *domain.ScoringResult) string {
+	return transactionEventID(eventNamespace, &card.Transaction)
+}
+
+func transactionEventID(namespace uuid.UUID, transaction *domain.TransactionAnalysis) string {
+	name := transaction.IdempotencyKey()
+	if name == "" {
+		name = transaction.Payment.Id
+	}
+	if name == "" {
+		return uuid.New().String()
+	}
+	return uuid.NewSHA1(namespace, []byte(name)).String()
+}
+
 func NewKafkaTransactionScoreCard(cli kafka.CloudEventsSender, log *zap.Logger) *KafkaTransactionScoreCard {
 	return &KafkaTransactionScoreCard{cli: cli, log: log}
 }
@@ -0,0 +1,28 @@
+package out
+
+import (
+	"fraud-scoring/internal/domain"
+	"testing"
+)
+
+func TestEventID_IsDeterministic(t *testing.T) {
+	card := &domain.ScoringResult{}
+	card.Transaction.Payment.Id = "pay-1"
+	card.Transaction.Payment.IdempotencyKey = "payment-key"
+	if eventID(card) != eventID(card) {
+		t.Error("Expected the same event id for the same transaction")
+	}
+
+	other := &domain.ScoringResult{}
+	other.Transaction.Payment.Id = "pay-1"
+	if eventID(card) == eventID(other) {
+		t.Error("Expected the idempotency key to tell the transactions apart")
+	}
+	if eventID(other) != eventID(other) {
+		t.Error("Expected the payment id to derive the event id without idempotency key")
+	}
+
+	if eventID(&domain.ScoringResult{}) == eventID(&domain.ScoringResult{}) {
+		t.Error("Expected a random event id without idempotency key nor payment id")
+	}
+}
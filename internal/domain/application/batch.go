@@ -0,0 +1,83 @@
+package application
+
+import (
+	"context"
+	"fraud-scoring/internal/domain"
+	"sync"
+)
+
+// BatchItem is one transaction of a batch. Err is set when the caller could not read the transaction, the item is
+// then answered with it without being scored. Vet, when set, vets the transaction as VettedAssessment does.
+type BatchItem struct {
+	// Key identifies the item in its result, such as its payment id.
+	Key         string
+	Transaction *domain.TransactionAnalysis
+	Vet         Vet
+	Err         error
+}
+
+type BatchResult struct {
+	Key    string
+	Result *domain.ScoringResult
+	Err    error
+}
+
+// AssessBatch scores the items read from items, with at most BatchConcurrency assessments in flight, and sends their
+// results to results in the order the items were received. An item that fails only fails its own result. The items
+// of the batch share their history lookups, every distinct lookup goes once to the repository, and are deduplicated by
+// their idempotency keys as single transactions are. results is closed once
+// items is closed and every item read is answered, or when ctx is done.
+func (prs *PaymentRiskScoring) AssessBatch(ctx context.Context, items <-chan BatchItem, results chan<- BatchResult) {
+	defer close(results)
+	batch := &PaymentRiskScoring{utr: newSharedHistory(prs.utr), tsc: prs.tsc, results: prs.results, cfg: prs.cfg, log: prs.log}
+	concurrency := prs.cfg.BatchConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	pending := make(chan chan BatchResult, concurrency)
+	var wg sync.WaitGroup
+	go func() {
+		defer close(pending)
+		for {
+			var item BatchItem
+			var ok bool
+			select {
+			case item, ok = <-items:
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			res := make(chan BatchResult, 1)
+			pending <- res
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				res <- batch.assessItem(ctx, item)
+			}()
+		}
+	}()
+	for res := range pending {
+		select {
+		case results <- <-res:
+		case <-ctx.Done():
+		}
+	}
+	wg.Wait()
+}
+
+func (prs *PaymentRiskScoring) assessItem(ctx context.Context, item BatchItem) BatchResult {
+	if item.Err != nil {
+		return BatchResult{Key: item.Key, Err: item.Err}
+	}
+	result, err := prs.VettedAssessment(ctx, item.Transaction, item.Vet)
+	return BatchResult{Key: item.Key, Result: result, Err: err}
+}
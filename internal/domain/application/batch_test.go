@@ -0,0 +1,231 @@
+package application
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"fraud-scoring/internal/domain"
+	"fraud-scoring/internal/domain/application/errors"
+	"fraud-scoring/internal/domain/history"
+	"fraud-scoring/internal/domain/repositories"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func assessBatch(prs *PaymentRiskScoring, batch []BatchItem) []BatchResult {
+	items := make(chan BatchItem)
+	results := make(chan BatchResult)
+	go func() {
+		defer close(items)
+		for _, item := range batch {
+			items <- item
+		}
+	}()
+	go prs.AssessBatch(context.Background(), items, results)
+	var answered []BatchResult
+	for res := range results {
+		answered = append(answered, res)
+	}
+	return answered
+}
+
+func TestPaymentRiskScoring_AssessBatch_KeepsOrderAndItemErrors(t *testing.T) {
+	mockUTR := &mockUserTransactionsRepository{
+		lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+			if document == "broken" {
+				return nil, stderrors.New("history unavailable")
+			}
+			// Earlier items answer last, the results must keep their order anyway.
+			time.Sleep(time.Duration(len(document)) * time.Millisecond)
+			return createLastOrder(), nil
+		},
+		averageBaselineFunc: func(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+			return createAverageBaseline(), nil
+		},
+	}
+	config := createScoringConfig()
+	config.BatchConcurrency = 4
+	prs := NewPaymentRiskScoring(mockUTR, &mockTransactionScoreCard{}, nil, config, zaptest.NewLogger(t))
+
+	rejected := stderrors.New("invalid transaction")
+	var batch []BatchItem
+	for i := 0; i < 8; i++ {
+		tx := createValidTransactionAnalysis()
+		tx.Payment.Id = fmt.Sprintf("payment-%d", i)
+		tx.Participants.Buyer.Document = fmt.Sprintf("%0*d", 20-i, i)
+		item := BatchItem{Key: tx.Payment.Id, Transaction: tx}
+		switch i {
+		case 2:
+			tx.Participants.Buyer.Document = "broken"
+		case 5:
+			item = BatchItem{Key: tx.Payment.Id, Err: rejected}
+		}
+		batch = append(batch, item)
+	}
+
+	results := assessBatch(prs, batch)
+
+	if len(results) != len(batch) {
+		t.Fatalf("Expected %d results, got %d", len(batch), len(results))
+	}
+	for i, res := range results {
+		if res.Key != batch[i].Key {
+			t.Errorf("Expected result %d for %s, got %s", i, batch[i].Key, res.Key)
+		}
+		switch i {
+		case 2:
+			if res.Err == nil {
+				t.Errorf("Expected the history error for %s", res.Key)
+			}
+		case 5:
+			if !stderrors.Is(res.Err, rejected) || res.Result != nil {
+				t.Errorf("Expected %s to be answered with its own error, got %+v", res.Key, res)
+			}
+		default:
+			if res.Err != nil || res.Result == nil {
+				t.Errorf("Expected %s to be scored, got %v", res.Key, res.Err)
+			}
+		}
+	}
+}
+
+func TestPaymentRiskScoring_AssessBatch_SharesHistoryLookups(t *testing.T) {
+	var lastOrders, baselines atomic.Int32
+	mockUTR := &mockUserTransactionsRepository{
+		lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+			lastOrders.Add(1)
+			time.Sleep(10 * time.Millisecond)
+			return createLastOrder(), nil
+		},
+		averageBaselineFunc: func(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+			baselines.Add(1)
+			return createAverageBaseline(), nil
+		},
+	}
+	config := createScoringConfig()
+	config.BatchConcurrency = 8
+	prs := NewPaymentRiskScoring(mockUTR, &mockTransactionScoreCard{}, nil, config, zaptest.NewLogger(t))
+
+	at := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	var batch []BatchItem
+	for i := 0; i < 16; i++ {
+		tx := createValidTransactionAnalysis()
+		tx.Payment.Id = fmt.Sprintf("payment-%d", i)
+		tx.Order.At = at.Add(time.Duration(i) * time.Minute)
+		batch = append(batch, BatchItem{Key: tx.Payment.Id, Transaction: tx})
+	}
+
+	for _, res := range assessBatch(prs, batch) {
+		if res.Err != nil {
+			t.Fatalf("Expected %s to be scored, got %v", res.Key, res.Err)
+		}
+	}
+	if n := lastOrders.Load(); n != 1 {
+		t.Errorf("Expected the last order of the buyer to be fetched once, got %d", n)
+	}
+	if n := baselines.Load(); n != 1 {
+		t.Errorf("Expected the baseline of the month to be fetched once, got %d", n)
+	}
+}
+
+func TestPaymentRiskScoring_AssessBatch_RetriesFailedLookups(t *testing.T) {
+	var calls atomic.Int32
+	mockUTR := &mockUserTransactionsRepository{
+		lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+			if calls.Add(1) == 1 {
+				return nil, stderrors.New("history unavailable")
+			}
+			return createLastOrder(), nil
+		},
+		averageBaselineFunc: func(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+			return createAverageBaseline(), nil
+		},
+	}
+	config := createScoringConfig()
+	config.BatchConcurrency = 1
+	prs := NewPaymentRiskScoring(mockUTR, &mockTransactionScoreCard{}, nil, config, zaptest.NewLogger(t))
+
+	batch := []BatchItem{
+		{Key: "payment-1", Transaction: createValidTransactionAnalysis()},
+		{Key: "payment-2", Transaction: createValidTransactionAnalysis()},
+	}
+	results := assessBatch(prs, batch)
+
+	if results[0].Err == nil {
+		t.Error("Expected the first item to fail with the history error")
+	}
+	if results[1].Err != nil {
+		t.Errorf("Expected the second item to fetch the last order again, got %v", results[1].Err)
+	}
+}
+
+func TestPaymentRiskScoring_AssessBatch_DeduplicatesByIdempotencyKey(t *testing.T) {
+	var lookups, stores atomic.Int32
+	mockUTR := &mockUserTransactionsRepository{
+		lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+			lookups.Add(1)
+			return createLastOrder(), nil
+		},
+		averageBaselineFunc: func(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+			return createAverageBaseline(), nil
+		},
+	}
+	mockTSC := &mockTransactionScoreCard{
+		storeFunc: func(ctx context.Context, scoreCard *domain.ScoringResult) error {
+			stores.Add(1)
+			return nil
+		},
+	}
+	config := createScoringConfig()
+	config.BatchConcurrency = 1
+	results := &mockScoringResults{cards: map[string]repositories.ScoredTransaction{}}
+	prs := NewPaymentRiskScoring(mockUTR, mockTSC, results, config, zaptest.NewLogger(t))
+
+	transaction := createValidTransactionAnalysis()
+	transaction.Payment.IdempotencyKey = "payment-key"
+	if _, err := prs.Assessment(context.Background(), transaction); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	repeated := *transaction
+	reused := *transaction
+	reused.Payment.Amount = "999.00"
+	answered := assessBatch(prs, []BatchItem{
+		{Key: "repeated", Transaction: &repeated},
+		{Key: "reused", Transaction: &reused},
+	})
+
+	if answered[0].Err != nil || answered[0].Result == nil || answered[0].Result.Transaction.Payment.Amount != transaction.Payment.Amount {
+		t.Errorf("Expected the repeated key to get the original scorecard, got %+v", answered[0])
+	}
+	if lookups.Load() != 1 || stores.Load() != 1 {
+		t.Errorf("Expected the repeated key not to be scored again, got %d lookups and %d stores", lookups.Load(), stores.Load())
+	}
+	var conflict errors.IdempotencyConflict
+	if !stderrors.As(answered[1].Err, &conflict) || !stderrors.Is(answered[1].Err, errors.ErrIdempotencyKeyReused) {
+		t.Errorf("Expected a conflict for the key reused by a different transaction, got %v", answered[1].Err)
+	}
+}
+
+func TestPaymentRiskScoring_AssessBatch_StopsWithContext(t *testing.T) {
+	prs := NewPaymentRiskScoring(&mockUserTransactionsRepository{}, &mockTransactionScoreCard{}, nil, createScoringConfig(), zaptest.NewLogger(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	items := make(chan BatchItem)
+	results := make(chan BatchResult)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		prs.AssessBatch(ctx, items, results)
+	}()
+
+	cancel()
+	for range results {
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected AssessBatch to return once the context is done")
+	}
+}
@@ -7,3 +7,7 @@ type AverageTransactionsNotFound struct {
 func (atn AverageTransactionsNotFound) Error() string {
 	return "fail to retrieve avg transactions: " + atn.Err.Error()
 }
+
+func (atn AverageTransactionsNotFound) Unwrap() error {
+	return atn.Err
+}
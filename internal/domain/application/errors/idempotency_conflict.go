@@ -0,0 +1,23 @@
+package errors
+
+import "errors"
+
+var (
+	ErrIdempotencyKeyReused = errors.New("the key was used by a different transaction")
+	ErrScoringInProgress    = errors.New("the transaction is still being scored")
+)
+
+// IdempotencyConflict is returned for a transaction whose idempotency key is held by a different transaction, or by the
+// same one still being scored.
+type IdempotencyConflict struct {
+	Key string
+	Err error
+}
+
+func (ic IdempotencyConflict) Error() string {
+	return "idempotency key " + ic.Key + " conflicts: " + ic.Err.Error()
+}
+
+func (ic IdempotencyConflict) Unwrap() error {
+	return ic.Err
+}
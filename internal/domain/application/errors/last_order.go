@@ -7,3 +7,7 @@ type LastOrderNotFound struct {
 func (lon LastOrderNotFound) Error() string {
 	return "fail to retrieve last order: " + lon.Err.Error()
 }
+
+func (lon LastOrderNotFound) Unwrap() error {
+	return lon.Err
+}
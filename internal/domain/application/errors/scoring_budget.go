@@ -0,0 +1,13 @@
+package errors
+
+type ScoringBudgetExceeded struct {
+	Err error
+}
+
+func (sbe ScoringBudgetExceeded) Error() string {
+	return "scoring budget exceeded: " + sbe.Err.Error()
+}
+
+func (sbe ScoringBudgetExceeded) Unwrap() error {
+	return sbe.Err
+}
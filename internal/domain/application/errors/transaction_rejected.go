@@ -0,0 +1,25 @@
+package errors
+
+import "strings"
+
+// TransactionRejected is returned for a transaction the validation turned down, with the rules it breaks.
+type TransactionRejected struct {
+	Errors []string
+}
+
+func (tr TransactionRejected) Error() string {
+	return "transaction rejected by the validation: " + strings.Join(tr.Errors, ", ")
+}
+
+// RejectionFailed is returned when the rejection of a transaction failing the validation could not be published.
+type RejectionFailed struct {
+	Err error
+}
+
+func (rf RejectionFailed) Error() string {
+	return "failed to publish the rejection: " + rf.Err.Error()
+}
+
+func (rf RejectionFailed) Unwrap() error {
+	return rf.Err
+}
@@ -0,0 +1,57 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+var historyLookupDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "fraud_scoring_history_lookup_duration_seconds",
+	Help:    "Latency of the buyer history lookups made while scoring a transaction.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"lookup", "outcome"})
+
+// historyLookup is one independent read of buyer history needed to score a transaction.
+type historyLookup struct {
+	name  string
+	fetch func(ctx context.Context) error
+}
+
+// fetchHistory runs the lookups concurrently and waits for all of them. The first failure cancels the lookups still
+// in flight and is the error returned.
+func (prs *PaymentRiskScoring) fetchHistory(ctx context.Context, document string, lookups ...historyLookup) error {
+	g, gctx := errgroup.WithContext(ctx)
+	for _, l := range lookups {
+		l := l
+		g.Go(func() error {
+			start := time.Now()
+			err := l.fetch(gctx)
+			latency := time.Since(start)
+			outcome := "ok"
+			if err != nil {
+				outcome = "error"
+			}
+			historyLookupDuration.WithLabelValues(l.name, outcome).Observe(latency.Seconds())
+			switch {
+			case err == nil:
+				prs.log.Debug("history lookup finished", zap.String("lookup", l.name), zap.Duration("latency", latency))
+			case gctx.Err() != nil && ctx.Err() == nil:
+				prs.log.Debug("history lookup cancelled", zap.String("lookup", l.name), zap.Duration("latency", latency))
+			default:
+				prs.log.Error("history lookup failed",
+					zap.String("lookup", l.name),
+					zap.String("user_id", document),
+					zap.Duration("latency", latency),
+					zap.String("error", err.Error()),
+				)
+			}
+			return err
+		})
+	}
+	return g.Wait()
+}
@@ -0,0 +1,64 @@
+package application
+
+import (
+	"context"
+	stderrors "errors"
+	"fraud-scoring/internal/domain"
+	"fraud-scoring/internal/domain/application/errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var assessmentDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "fraud_scoring_assessment_duration_seconds",
+	Help:    "Latency of the assessments, from the first history lookup to the published score card.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"outcome"})
+
+// criterionScores buckets every possible total of the criteria, they subtract up to a few points each.
+var criterionScores = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "fraud_scoring_criterion_score",
+	Help:    "Points given by each criterion to the scored transactions, overall is their sum.",
+	Buckets: prometheus.LinearBuckets(-10, 1, 11),
+}, []string{"criterion"})
+
+var decisions = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "fraud_scoring_decisions_total",
+	Help: "Decisions of the published score cards.",
+}, []string{"decision"})
+
+// duplicatesConflict is the action counted for a transaction reusing the idempotency key of a different one.
+const duplicatesConflict = "conflict"
+
+var duplicates = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "fraud_scoring_duplicates_total",
+	Help: "Transactions delivered with the idempotency key of one already scored, per action: skip, reemit, or conflict when they differ from it.",
+}, []string{"action"})
+
+func observeAssessment(start time.Time, err error) {
+	outcome := "ok"
+	var budget errors.ScoringBudgetExceeded
+	switch {
+	case err == nil:
+	case stderrors.As(err, &errors.TransactionRejected{}):
+		outcome = "rejected"
+	case stderrors.As(err, &budget):
+		outcome = "budget_exceeded"
+	case stderrors.Is(err, context.Canceled):
+		outcome = "canceled"
+	default:
+		outcome = "error"
+	}
+	assessmentDuration.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+}
+
+func observeScoreCard(card *domain.ScoringResult) {
+	criterionScores.WithLabelValues("value").Observe(float64(card.Score.ValueScore.Score))
+	criterionScores.WithLabelValues("seller").Observe(float64(card.Score.SellerScore.Score))
+	criterionScores.WithLabelValues("average_value").Observe(float64(card.Score.AverageValueScore.Score))
+	criterionScores.WithLabelValues("currency").Observe(float64(card.Score.CurrencyScore.Score))
+	criterionScores.WithLabelValues("overall").Observe(float64(card.Score.Total()))
+	decisions.WithLabelValues(string(card.Decision)).Inc()
+}
@@ -0,0 +1,71 @@
+package application
+
+import (
+	"context"
+	"fraud-scoring/internal/domain"
+	"fraud-scoring/internal/domain/history"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestPaymentRiskScoring_Assessment_RecordsMetrics(t *testing.T) {
+	mockUTR := &mockUserTransactionsRepository{
+		lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+			return createLastOrder(), nil
+		},
+		averageBaselineFunc: func(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+			return createAverageBaseline(), nil
+		},
+	}
+	prs := NewPaymentRiskScoring(mockUTR, &mockTransactionScoreCard{}, nil, createScoringConfig(), zaptest.NewLogger(t))
+
+	approved := testutil.ToFloat64(decisions.WithLabelValues(string(domain.DecisionApprove)))
+
+	card, err := prs.Assessment(context.Background(), createValidTransactionAnalysis())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if card.Decision != domain.DecisionApprove {
+		t.Fatalf("Expected an approved transaction, got %s", card.Decision)
+	}
+	if got := testutil.ToFloat64(decisions.WithLabelValues(string(domain.DecisionApprove))); got != approved+1 {
+		t.Errorf("Expected approve decisions to grow by one, got %v after %v", got, approved)
+	}
+	if got := testutil.CollectAndCount(criterionScores); got != 5 {
+		t.Errorf("Expected a score distribution for the four criteria and the overall score, got %d", got)
+	}
+	if got := testutil.CollectAndCount(assessmentDuration); got == 0 {
+		t.Error("Expected the assessment latency to be recorded")
+	}
+}
+
+func TestPaymentRiskScoring_Assessment_RecordsBudgetOutcome(t *testing.T) {
+	mockUTR := &mockUserTransactionsRepository{
+		lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+	prs := NewPaymentRiskScoring(mockUTR, &mockTransactionScoreCard{}, nil, &ScoringConfig{Budget: 10 * time.Millisecond, BaselineMonths: 3}, zaptest.NewLogger(t))
+
+	before := assessments(t, "budget_exceeded")
+	if _, err := prs.Assessment(context.Background(), createValidTransactionAnalysis()); err == nil {
+		t.Fatal("Expected the assessment to run out of budget")
+	}
+	if got := assessments(t, "budget_exceeded"); got != before+1 {
+		t.Errorf("Expected one more budget_exceeded assessment, got %d after %d", got, before)
+	}
+}
+
+func assessments(t *testing.T, outcome string) uint64 {
+	var m dto.Metric
+	if err := assessmentDuration.WithLabelValues(outcome).(prometheus.Histogram).Write(&m); err != nil {
+		t.Fatalf("Failed to read the %s assessments: %v", outcome, err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
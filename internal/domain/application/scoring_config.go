@@ -0,0 +1,25 @@
+package application
+
+import (
+	"fraud-scoring/internal/domain"
+	"time"
+)
+
+// What an assessment does with a transaction whose idempotency key was already scored. Both answer with the original
+// scorecard, reemit publishes it again.
+const (
+	DuplicatesSkip   = "skip"
+	DuplicatesReemit = "reemit"
+)
+
+type ScoringConfig struct {
+	// Budget is the total time an assessment may take, from the first history lookup to the scorecard being stored.
+	Budget time.Duration
+	// BaselineMonths is how many months before a transaction make up the buyer's average value baseline.
+	BaselineMonths int
+	Decision       domain.DecisionPolicy
+	// BatchConcurrency is how many transactions of a batch are scored at the same time.
+	BatchConcurrency int
+	// Duplicates is DuplicatesSkip or DuplicatesReemit.
+	Duplicates string
+}
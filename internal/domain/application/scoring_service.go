@@ -1,47 +1,118 @@
 package application
 
 import (
+	"context"
+	stderrors "errors"
 	"fraud-scoring/internal/domain"
 	"fraud-scoring/internal/domain/application/errors"
+	"fraud-scoring/internal/domain/history"
 	"fraud-scoring/internal/domain/repositories"
 	"fraud-scoring/internal/domain/scoring"
 	"fraud-scoring/internal/domain/scoring/criteria"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
 type PaymentRiskScoring struct {
-	utr repositories.UserTransactionsRepository
-	tsc repositories.TransactionScoreCard
-	log *zap.Logger
+	utr     repositories.UserTransactionsRepository
+	tsc     repositories.TransactionScoreCard
+	results repositories.ScoringResults
+	cfg     *ScoringConfig
+	log     *zap.Logger
 }
 
-func (prs *PaymentRiskScoring) Assessment(order *domain.TransactionAnalysis) error {
+// Vet fails for an order that can't be scored, with a TransactionRejected when the order breaks the validation rules.
+// It runs once the order is known not to be a duplicate, so a transaction delivered again gets its scorecard whatever
+// the vet would say of it now.
+type Vet func(ctx context.Context, order *domain.TransactionAnalysis) error
+
+// Assessment scores order, stores its scorecard and returns it. An order with the idempotency key of one already
+// scored gets the scorecard of the first one instead, stored again or not as configured, unless it differs from it: it
+// fails with an IdempotencyConflict then, as it does while the first one is still being scored. A buyer without
+// history is scored without the criteria comparing with it.
+func (prs *PaymentRiskScoring) Assessment(ctx context.Context, order *domain.TransactionAnalysis) (*domain.ScoringResult, error) {
+	return prs.VettedAssessment(ctx, order, nil)
+}
+
+// VettedAssessment is Assessment with order vetted after the duplicate check and before being scored. An order the vet
+// fails isn't scored and fails with the error of the vet, a nil vet accepts every order.
+func (prs *PaymentRiskScoring) VettedAssessment(ctx context.Context, order *domain.TransactionAnalysis, vet Vet) (card *domain.ScoringResult, err error) {
+	ctx, span := tracer.Start(ctx, "PaymentRiskScoring.Assessment", trace.WithAttributes(
+		attribute.String("payment.id", order.Payment.Id),
+		attribute.String("seller.id", order.Participants.Seller.SellerId),
+	))
+	defer func(start time.Time) {
+		observeAssessment(start, err)
+		endSpan(span, err)
+	}(time.Now())
 	prs.log.Info("start to performing scoring in transaction",
 		zap.String("id", order.Payment.Id),
 		zap.String("user_id", order.Participants.Buyer.Document),
 		zap.String("seller_id", order.Participants.Seller.SellerId),
 	)
-	lastOrder, err := prs.utr.LastOrder(order.Participants.Buyer.Document)
+	if prs.cfg.Budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, prs.cfg.Budget)
+		defer cancel()
+	}
+	key := order.IdempotencyKey()
+	original, claimed, err := prs.claim(ctx, key, order)
 	if err != nil {
-		prs.log.Error("error to retrieve last transaction", zap.String("user_id", order.Participants.Buyer.Document))
-		return errors.LastOrderNotFound{Err: err}
+		return nil, err
 	}
-	avg, err := prs.utr.AverageTransactions(order.Participants.Buyer.Document, order.Order.At)
+	if original != nil {
+		span.SetAttributes(attribute.Bool("scoring.duplicate", true))
+		return prs.duplicate(ctx, original)
+	}
+	if claimed {
+		defer func() {
+			if err != nil {
+				prs.release(ctx, key)
+			}
+		}()
+	}
+	if vet != nil {
+		if err := vet(ctx, order); err != nil {
+			return nil, err
+		}
+	}
+	var lastOrder *history.LastOrder
+	var baseline *history.AverageBaseline
+	err = prs.fetchHistory(ctx, order.Participants.Buyer.Document,
+		historyLookup{name: "last_order", fetch: func(ctx context.Context) error {
+			lo, err := prs.utr.LastOrder(ctx, order.Participants.Buyer.Document)
+			if stderrors.As(err, &repositories.HistoryNotFound{}) {
+				lo, err = &history.LastOrder{}, nil
+			}
+			if err != nil {
+				return errors.LastOrderNotFound{Err: err}
+			}
+			lastOrder = lo
+			return nil
+		}},
+		historyLookup{name: "average_baseline", fetch: func(ctx context.Context) error {
+			ab, err := prs.utr.AverageBaseline(ctx, order.Participants.Buyer.Document, order.Order.At, prs.cfg.BaselineMonths)
+			if stderrors.As(err, &repositories.HistoryNotFound{}) {
+				ab, err = &history.AverageBaseline{}, nil
+			}
+			if err != nil {
+				return errors.AverageTransactionsNotFound{Err: err}
+			}
+			baseline = ab
+			return nil
+		}},
+	)
 	if err != nil {
-		prs.log.Error("error to retrieve avg transaction", zap.String("user_id", order.Participants.Buyer.Document))
-		return errors.AverageTransactionsNotFound{Err: err}
-	}
-	ac := &criteria.AverageValueCriteria{}
-	sc := &criteria.SellerCriteria{Next: ac}
-	cc := &criteria.CurrencyCriteria{Next: sc}
-	vc := &criteria.ValueCriteria{Next: cc}
-	ti := scoring.TransactionRiskScoreInput{
-		Average:     avg,
+		return nil, budgetExceeded(ctx, err)
+	}
+	scores := prs.score(ctx, scoring.TransactionRiskScoreInput{
+		Baseline:    baseline,
 		Last:        lastOrder,
 		Transaction: order,
-	}
-	scores := &scoring.TransactionRiskFactors{}
-	vc.Execute(ti, scores)
+	})
 
 	scoreCard := &domain.ScoringResult{
 		Score: domain.ScoreCard{
@@ -51,20 +122,135 @@ func (prs *PaymentRiskScoring) Assessment(order *domain.TransactionAnalysis) err
 			CurrencyScore:     domain.CurrencyScoreCard{Score: scores.CurrencyScore.Scoring},
 		},
 		Transaction: *order,
+		Provenance: domain.ScoreProvenance{
+			LastOrder:       lastOrder.Provenance,
+			AverageBaseline: baseline.Provenance,
+		},
 	}
-	errSc := prs.tsc.Store(scoreCard)
+	scoreCard.Decision = prs.cfg.Decision.Decide(scoreCard.Score)
+	errSc := prs.tsc.Store(ctx, scoreCard)
 	if errSc != nil {
 		prs.log.Error("error to store scorecard in database", zap.String("user_id", order.Participants.Buyer.Document))
-		return errSc
+		return nil, budgetExceeded(ctx, errSc)
 	}
+	if claimed {
+		prs.remember(ctx, key, scoreCard)
+	}
+	observeScoreCard(scoreCard)
+	span.SetAttributes(
+		attribute.String("scoring.decision", string(scoreCard.Decision)),
+		attribute.Int("scoring.total", scoreCard.Score.Total()),
+	)
 	prs.log.Info("transaction was scored",
 		zap.String("id", order.Payment.Id),
 		zap.String("user_id", order.Participants.Buyer.Document),
 		zap.String("seller_id", order.Participants.Seller.SellerId),
+		zap.String("decision", string(scoreCard.Decision)),
+	)
+	return scoreCard, nil
+}
+
+// claim holds the idempotency key of order while it is scored, and tells whether it did. It returns the scorecard of
+// the transaction already scored with the key instead, or an IdempotencyConflict when the key is held by a different
+// transaction or the same one is still being scored. A failing claim is not worth failing the assessment for, the
+// transaction is scored without holding the key.
+func (prs *PaymentRiskScoring) claim(ctx context.Context, key string, order *domain.TransactionAnalysis) (*domain.ScoringResult, bool, error) {
+	if prs.results == nil || key == "" {
+		return nil, false, nil
+	}
+	fingerprint := order.Fingerprint()
+	held, claimed, err := prs.results.Claim(ctx, key, fingerprint)
+	if err != nil {
+		prs.log.Warn("failed to claim idempotency key", zap.String("idempotency_key", key), zap.Error(err))
+		return nil, false, nil
+	}
+	switch {
+	case claimed:
+		return nil, true, nil
+	case held.Fingerprint != fingerprint:
+		duplicates.WithLabelValues(duplicatesConflict).Inc()
+		return nil, false, errors.IdempotencyConflict{Key: key, Err: errors.ErrIdempotencyKeyReused}
+	case held.Card == nil:
+		return nil, false, errors.IdempotencyConflict{Key: key, Err: errors.ErrScoringInProgress}
+	default:
+		return held.Card, false, nil
+	}
+}
+
+// release frees the idempotency key of a transaction that could not be scored, even once the assessment ran out of
+// time. A key that could not be freed is held until it expires.
+func (prs *PaymentRiskScoring) release(ctx context.Context, key string) {
+	if err := prs.results.Release(context.WithoutCancel(ctx), key); err != nil {
+		prs.log.Warn("failed to release idempotency key", zap.String("idempotency_key", key), zap.Error(err))
+	}
+}
+
+// duplicate answers a transaction delivered again with the scorecard it got the first time, storing it again when
+// duplicates are re-emitted.
+func (prs *PaymentRiskScoring) duplicate(ctx context.Context, card *domain.ScoringResult) (*domain.ScoringResult, error) {
+	action := DuplicatesSkip
+	if prs.cfg.Duplicates == DuplicatesReemit {
+		if err := prs.tsc.Store(ctx, card); err != nil {
+			prs.log.Error("error to store scorecard again", zap.String("id", card.Transaction.Payment.Id), zap.Error(err))
+			return nil, budgetExceeded(ctx, err)
+		}
+		action = DuplicatesReemit
+	}
+	duplicates.WithLabelValues(action).Inc()
+	prs.log.Info("transaction was already scored",
+		zap.String("id", card.Transaction.Payment.Id),
+		zap.String("idempotency_key", card.Transaction.IdempotencyKey()),
+		zap.String("action", action),
 	)
-	return nil
+	return card, nil
+}
+
+// remember keeps the stored scorecard for the transactions delivered again. A scorecard that could not be kept is
+// published again on redelivery, consumers dedupe it by its event id.
+func (prs *PaymentRiskScoring) remember(ctx context.Context, key string, card *domain.ScoringResult) {
+	if prs.results == nil || key == "" {
+		return
+	}
+	if err := prs.results.Put(ctx, key, card); err != nil {
+		prs.log.Warn("failed to keep scored transaction", zap.String("idempotency_key", key), zap.Error(err))
+	}
+}
+
+// score runs the criteria one after the other, each on its own span.
+func (prs *PaymentRiskScoring) score(ctx context.Context, input scoring.TransactionRiskScoreInput) *scoring.TransactionRiskFactors {
+	rules := []struct {
+		name string
+		rule scoring.Rule
+	}{
+		{name: "value", rule: &criteria.ValueCriteria{}},
+		{name: "currency", rule: &criteria.CurrencyCriteria{}},
+		{name: "seller", rule: &criteria.SellerCriteria{}},
+		{name: "average_value", rule: &criteria.AverageValueCriteria{}},
+	}
+	scores := &scoring.TransactionRiskFactors{}
+	for _, r := range rules {
+		_, span := tracer.Start(ctx, "criteria."+r.name)
+		r.rule.Execute(input, scores)
+		span.End()
+	}
+	return scores
+}
+
+// budgetExceeded reports err as a ScoringBudgetExceeded when it happened because the assessment ran out of time.
+func budgetExceeded(ctx context.Context, err error) error {
+	if stderrors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return errors.ScoringBudgetExceeded{Err: err}
+	}
+	return err
 }
 
-func NewPaymentRiskScoring(utr repositories.UserTransactionsRepository, tsc repositories.TransactionScoreCard, log *zap.Logger) *PaymentRiskScoring {
-	return &PaymentRiskScoring{utr: utr, tsc: tsc, log: log}
+// NewPaymentRiskScoring returns the risk assessment, it doesn't dedupe the transactions when results is nil.
+func NewPaymentRiskScoring(
+	utr repositories.UserTransactionsRepository,
+	tsc repositories.TransactionScoreCard,
+	results repositories.ScoringResults,
+	cfg *ScoringConfig,
+	log *zap.Logger,
+) *PaymentRiskScoring {
+	return &PaymentRiskScoring{utr: utr, tsc: tsc, results: results, cfg: cfg, log: log}
 }
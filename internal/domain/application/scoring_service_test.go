@@ -1,9 +1,13 @@
 package application
 
 import (
+	"context"
+	stderrors "errors"
 	"fraud-scoring/internal/domain"
 	"fraud-scoring/internal/domain/application/errors"
+	"fraud-scoring/internal/domain/history"
 	"fraud-scoring/internal/domain/repositories"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -13,35 +17,66 @@ import (
 
 // Mock implementations for testing
 type mockUserTransactionsRepository struct {
-	lastOrderFunc           func(string) (*domain.TransactionAnalysis, error)
-	averageTransactionsFunc func(string, time.Time) (*domain.UserMonthAverage, error)
+	lastOrderFunc           func(context.Context, string) (*history.LastOrder, error)
+	averageTransactionsFunc func(context.Context, string, time.Time) (*history.AveragePayment, error)
+	averageBaselineFunc     func(context.Context, string, time.Time, int) (*history.AverageBaseline, error)
 }
 
-func (m *mockUserTransactionsRepository) LastOrder(document string) (*domain.TransactionAnalysis, error) {
+func (m *mockUserTransactionsRepository) LastOrder(ctx context.Context, document string) (*history.LastOrder, error) {
 	if m.lastOrderFunc != nil {
-		return m.lastOrderFunc(document)
+		return m.lastOrderFunc(ctx, document)
 	}
 	return nil, nil
 }
 
-func (m *mockUserTransactionsRepository) AverageTransactions(document string, date time.Time) (*domain.UserMonthAverage, error) {
+func (m *mockUserTransactionsRepository) AverageTransactions(ctx context.Context, document string, date time.Time) (*history.AveragePayment, error) {
 	if m.averageTransactionsFunc != nil {
-		return m.averageTransactionsFunc(document, date)
+		return m.averageTransactionsFunc(ctx, document, date)
+	}
+	return nil, nil
+}
+
+func (m *mockUserTransactionsRepository) AverageBaseline(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+	if m.averageBaselineFunc != nil {
+		return m.averageBaselineFunc(ctx, document, date, months)
 	}
 	return nil, nil
 }
 
 type mockTransactionScoreCard struct {
-	storeFunc func(*domain.ScoringResult) error
+	storeFunc func(context.Context, *domain.ScoringResult) error
 }
 
-func (m *mockTransactionScoreCard) Store(scoreCard *domain.ScoringResult) error {
+func (m *mockTransactionScoreCard) Store(ctx context.Context, scoreCard *domain.ScoringResult) error {
 	if m.storeFunc != nil {
-		return m.storeFunc(scoreCard)
+		return m.storeFunc(ctx, scoreCard)
 	}
 	return nil
 }
 
+func createScoringConfig() *ScoringConfig {
+	return &ScoringConfig{
+		Budget:         time.Second,
+		BaselineMonths: 3,
+		Decision:       domain.DecisionPolicy{ReviewAt: -3, DeclineAt: -6},
+	}
+}
+
+func createLastOrder() *history.LastOrder {
+	return &history.LastOrder{
+		SellerId: "seller-123",
+		Currency: "USD",
+		Amount:   "100.00",
+	}
+}
+
+func createAverageBaseline() *history.AverageBaseline {
+	return &history.AverageBaseline{
+		Months: []history.AveragePayment{{Month: "2024-01", Amount: "1000.00"}},
+		Amount: "1000.00",
+	}
+}
+
 // Helper function to create a valid transaction analysis
 func createValidTransactionAnalysis() *domain.TransactionAnalysis {
 	return &domain.TransactionAnalysis{
@@ -76,51 +111,86 @@ func TestPaymentRiskScoring_Assessment_Success(t *testing.T) {
 	defer logger.Sync()
 
 	mockUTR := &mockUserTransactionsRepository{
-		lastOrderFunc: func(document string) (*domain.TransactionAnalysis, error) {
-			return createValidTransactionAnalysis(), nil
+		lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+			return createLastOrder(), nil
 		},
-		averageTransactionsFunc: func(document string, date time.Time) (*domain.UserMonthAverage, error) {
-			return &domain.UserMonthAverage{
-				Document: document,
-				Month:    "2024-01",
-				Total:    "1000.00",
-				Count:    10,
-			}, nil
+		averageBaselineFunc: func(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+			return createAverageBaseline(), nil
 		},
 	}
 
 	mockTSC := &mockTransactionScoreCard{
-		storeFunc: func(scoreCard *domain.ScoringResult) error {
+		storeFunc: func(ctx context.Context, scoreCard *domain.ScoringResult) error {
 			return nil
 		},
 	}
 
-	prs := NewPaymentRiskScoring(mockUTR, mockTSC, logger)
+	prs := NewPaymentRiskScoring(mockUTR, mockTSC, nil, createScoringConfig(), logger)
 	transaction := createValidTransactionAnalysis()
 
-	err := prs.Assessment(transaction)
+	_, err := prs.Assessment(context.Background(), transaction)
 
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
 }
 
+func TestPaymentRiskScoring_Assessment_BuyerWithoutPriorMonths(t *testing.T) {
+	mockUTR := &mockUserTransactionsRepository{
+		lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+			return createLastOrder(), nil
+		},
+		averageBaselineFunc: func(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+			return &history.AverageBaseline{}, nil
+		},
+	}
+	prs := NewPaymentRiskScoring(mockUTR, &mockTransactionScoreCard{}, nil, createScoringConfig(), zaptest.NewLogger(t))
+
+	card, err := prs.Assessment(context.Background(), createValidTransactionAnalysis())
+	if err != nil {
+		t.Fatalf("Expected a buyer without prior months to be scored, got %v", err)
+	}
+	if card.Score.AverageValueScore.Score != 0 {
+		t.Errorf("Expected no average value penalty without baseline, got %d", card.Score.AverageValueScore.Score)
+	}
+}
+
+func TestPaymentRiskScoring_Assessment_FirstPurchase(t *testing.T) {
+	mockUTR := &mockUserTransactionsRepository{
+		lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+			return nil, repositories.HistoryNotFound{Err: stderrors.New("user not found")}
+		},
+		averageBaselineFunc: func(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+			return nil, repositories.HistoryNotFound{Err: stderrors.New("user not found")}
+		},
+	}
+	prs := NewPaymentRiskScoring(mockUTR, &mockTransactionScoreCard{}, nil, createScoringConfig(), zaptest.NewLogger(t))
+
+	card, err := prs.Assessment(context.Background(), createValidTransactionAnalysis())
+	if err != nil {
+		t.Fatalf("Expected a first purchase to be scored, got %v", err)
+	}
+	if total := card.Score.Total(); total != 0 {
+		t.Errorf("Expected a first purchase not to be penalised, got %+v", card.Score)
+	}
+}
+
 func TestPaymentRiskScoring_Assessment_LastOrderError(t *testing.T) {
 	logger := zaptest.NewLogger(t)
 	defer logger.Sync()
 
 	mockUTR := &mockUserTransactionsRepository{
-		lastOrderFunc: func(document string) (*domain.TransactionAnalysis, error) {
-			return nil, errors.New("database error")
+		lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+			return nil, stderrors.New("database error")
 		},
 	}
 
 	mockTSC := &mockTransactionScoreCard{}
 
-	prs := NewPaymentRiskScoring(mockUTR, mockTSC, logger)
+	prs := NewPaymentRiskScoring(mockUTR, mockTSC, nil, createScoringConfig(), logger)
 	transaction := createValidTransactionAnalysis()
 
-	err := prs.Assessment(transaction)
+	_, err := prs.Assessment(context.Background(), transaction)
 
 	if err == nil {
 		t.Error("Expected error, got none")
@@ -136,20 +206,20 @@ func TestPaymentRiskScoring_Assessment_AverageTransactionsError(t *testing.T) {
 	defer logger.Sync()
 
 	mockUTR := &mockUserTransactionsRepository{
-		lastOrderFunc: func(document string) (*domain.TransactionAnalysis, error) {
-			return createValidTransactionAnalysis(), nil
+		lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+			return createLastOrder(), nil
 		},
-		averageTransactionsFunc: func(document string, date time.Time) (*domain.UserMonthAverage, error) {
-			return nil, errors.New("database error")
+		averageBaselineFunc: func(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+			return nil, stderrors.New("database error")
 		},
 	}
 
 	mockTSC := &mockTransactionScoreCard{}
 
-	prs := NewPaymentRiskScoring(mockUTR, mockTSC, logger)
+	prs := NewPaymentRiskScoring(mockUTR, mockTSC, nil, createScoringConfig(), logger)
 	transaction := createValidTransactionAnalysis()
 
-	err := prs.Assessment(transaction)
+	_, err := prs.Assessment(context.Background(), transaction)
 
 	if err == nil {
 		t.Error("Expected error, got none")
@@ -165,29 +235,24 @@ func TestPaymentRiskScoring_Assessment_StoreError(t *testing.T) {
 	defer logger.Sync()
 
 	mockUTR := &mockUserTransactionsRepository{
-		lastOrderFunc: func(document string) (*domain.TransactionAnalysis, error) {
-			return createValidTransactionAnalysis(), nil
+		lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+			return createLastOrder(), nil
 		},
-		averageTransactionsFunc: func(document string, date time.Time) (*domain.UserMonthAverage, error) {
-			return &domain.UserMonthAverage{
-				Document: document,
-				Month:    "2024-01",
-				Total:    "1000.00",
-				Count:    10,
-			}, nil
+		averageBaselineFunc: func(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+			return createAverageBaseline(), nil
 		},
 	}
 
 	mockTSC := &mockTransactionScoreCard{
-		storeFunc: func(scoreCard *domain.ScoringResult) error {
-			return errors.New("storage error")
+		storeFunc: func(ctx context.Context, scoreCard *domain.ScoringResult) error {
+			return stderrors.New("storage error")
 		},
 	}
 
-	prs := NewPaymentRiskScoring(mockUTR, mockTSC, logger)
+	prs := NewPaymentRiskScoring(mockUTR, mockTSC, nil, createScoringConfig(), logger)
 	transaction := createValidTransactionAnalysis()
 
-	err := prs.Assessment(transaction)
+	_, err := prs.Assessment(context.Background(), transaction)
 
 	if err == nil {
 		t.Error("Expected error, got none")
@@ -205,7 +270,7 @@ func TestPaymentRiskScoring_Assessment_NilTransaction(t *testing.T) {
 	mockUTR := &mockUserTransactionsRepository{}
 	mockTSC := &mockTransactionScoreCard{}
 
-	prs := NewPaymentRiskScoring(mockUTR, mockTSC, logger)
+	prs := NewPaymentRiskScoring(mockUTR, mockTSC, nil, createScoringConfig(), logger)
 
 	// This should panic or handle nil gracefully
 	defer func() {
@@ -214,7 +279,7 @@ func TestPaymentRiskScoring_Assessment_NilTransaction(t *testing.T) {
 		}
 	}()
 
-	err := prs.Assessment(nil)
+	_, err := prs.Assessment(context.Background(), nil)
 	if err == nil {
 		t.Error("Expected error for nil transaction")
 	}
@@ -227,30 +292,27 @@ func TestPaymentRiskScoring_Assessment_ScoreCardGeneration(t *testing.T) {
 	var storedScoreCard *domain.ScoringResult
 
 	mockUTR := &mockUserTransactionsRepository{
-		lastOrderFunc: func(document string) (*domain.TransactionAnalysis, error) {
-			return createValidTransactionAnalysis(), nil
+		lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+			return createLastOrder(), nil
 		},
-		averageTransactionsFunc: func(document string, date time.Time) (*domain.UserMonthAverage, error) {
-			return &domain.UserMonthAverage{
-				Document: document,
-				Month:    "2024-01",
-				Total:    "1000.00",
-				Count:    10,
-			}, nil
+		averageBaselineFunc: func(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+			baseline := createAverageBaseline()
+			baseline.Provenance = history.Provenance{Source: history.SourceCache}
+			return baseline, nil
 		},
 	}
 
 	mockTSC := &mockTransactionScoreCard{
-		storeFunc: func(scoreCard *domain.ScoringResult) error {
+		storeFunc: func(ctx context.Context, scoreCard *domain.ScoringResult) error {
 			storedScoreCard = scoreCard
 			return nil
 		},
 	}
 
-	prs := NewPaymentRiskScoring(mockUTR, mockTSC, logger)
+	prs := NewPaymentRiskScoring(mockUTR, mockTSC, nil, createScoringConfig(), logger)
 	transaction := createValidTransactionAnalysis()
 
-	err := prs.Assessment(transaction)
+	result, err := prs.Assessment(context.Background(), transaction)
 
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
@@ -261,17 +323,30 @@ func TestPaymentRiskScoring_Assessment_ScoreCardGeneration(t *testing.T) {
 		return
 	}
 
+	if result != storedScoreCard {
+		t.Error("Expected the stored scoreCard to be returned")
+	}
+
+	if storedScoreCard.Decision == "" {
+		t.Error("Expected a decision on the scoreCard")
+	}
+
 	// Verify scoreCard structure
 	if storedScoreCard.Transaction.Payment.Id != transaction.Payment.Id {
-		t.Errorf("Expected transaction ID %s, got %s", 
+		t.Errorf("Expected transaction ID %s, got %s",
 			transaction.Payment.Id, storedScoreCard.Transaction.Payment.Id)
 	}
 
 	if storedScoreCard.Transaction.Participants.Buyer.Document != transaction.Participants.Buyer.Document {
-		t.Errorf("Expected buyer document %s, got %s", 
+		t.Errorf("Expected buyer document %s, got %s",
 			transaction.Participants.Buyer.Document, storedScoreCard.Transaction.Participants.Buyer.Document)
 	}
 
+	if storedScoreCard.Provenance.AverageBaseline.Source != history.SourceCache {
+		t.Errorf("Expected baseline provenance %s, got %s",
+			history.SourceCache, storedScoreCard.Provenance.AverageBaseline.Source)
+	}
+
 	// Verify that scores are within valid range (0-100)
 	scores := []int{
 		storedScoreCard.Score.ValueScore.Score,
@@ -294,7 +369,7 @@ func TestNewPaymentRiskScoring(t *testing.T) {
 	mockUTR := &mockUserTransactionsRepository{}
 	mockTSC := &mockTransactionScoreCard{}
 
-	prs := NewPaymentRiskScoring(mockUTR, mockTSC, logger)
+	prs := NewPaymentRiskScoring(mockUTR, mockTSC, nil, createScoringConfig(), logger)
 
 	if prs == nil {
 		t.Error("Expected PaymentRiskScoring instance, got nil")
@@ -308,6 +383,10 @@ func TestNewPaymentRiskScoring(t *testing.T) {
 		t.Error("Expected transaction score card repository to be set")
 	}
 
+	if prs.cfg == nil {
+		t.Error("Expected scoring config to be set")
+	}
+
 	if prs.log != logger {
 		t.Error("Expected logger to be set")
 	}
@@ -322,30 +401,25 @@ func TestPaymentRiskScoring_Assessment_WithDifferentCurrencies(t *testing.T) {
 	for _, currency := range currencies {
 		t.Run("Currency_"+currency, func(t *testing.T) {
 			mockUTR := &mockUserTransactionsRepository{
-				lastOrderFunc: func(document string) (*domain.TransactionAnalysis, error) {
-					return createValidTransactionAnalysis(), nil
+				lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+					return createLastOrder(), nil
 				},
-				averageTransactionsFunc: func(document string, date time.Time) (*domain.UserMonthAverage, error) {
-					return &domain.UserMonthAverage{
-						Document: document,
-						Month:    "2024-01",
-						Total:    "1000.00",
-						Count:    10,
-					}, nil
+				averageBaselineFunc: func(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+					return createAverageBaseline(), nil
 				},
 			}
 
 			mockTSC := &mockTransactionScoreCard{
-				storeFunc: func(scoreCard *domain.ScoringResult) error {
+				storeFunc: func(ctx context.Context, scoreCard *domain.ScoringResult) error {
 					return nil
 				},
 			}
 
-			prs := NewPaymentRiskScoring(mockUTR, mockTSC, logger)
+			prs := NewPaymentRiskScoring(mockUTR, mockTSC, nil, createScoringConfig(), logger)
 			transaction := createValidTransactionAnalysis()
 			transaction.Payment.Currency = currency
 
-			err := prs.Assessment(transaction)
+			_, err := prs.Assessment(context.Background(), transaction)
 
 			if err != nil {
 				t.Errorf("Expected no error for currency %s, got %v", currency, err)
@@ -363,30 +437,25 @@ func TestPaymentRiskScoring_Assessment_WithDifferentAmounts(t *testing.T) {
 	for _, amount := range amounts {
 		t.Run("Amount_"+amount, func(t *testing.T) {
 			mockUTR := &mockUserTransactionsRepository{
-				lastOrderFunc: func(document string) (*domain.TransactionAnalysis, error) {
-					return createValidTransactionAnalysis(), nil
+				lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+					return createLastOrder(), nil
 				},
-				averageTransactionsFunc: func(document string, date time.Time) (*domain.UserMonthAverage, error) {
-					return &domain.UserMonthAverage{
-						Document: document,
-						Month:    "2024-01",
-						Total:    "1000.00",
-						Count:    10,
-					}, nil
+				averageBaselineFunc: func(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+					return createAverageBaseline(), nil
 				},
 			}
 
 			mockTSC := &mockTransactionScoreCard{
-				storeFunc: func(scoreCard *domain.ScoringResult) error {
+				storeFunc: func(ctx context.Context, scoreCard *domain.ScoringResult) error {
 					return nil
 				},
 			}
 
-			prs := NewPaymentRiskScoring(mockUTR, mockTSC, logger)
+			prs := NewPaymentRiskScoring(mockUTR, mockTSC, nil, createScoringConfig(), logger)
 			transaction := createValidTransactionAnalysis()
 			transaction.Payment.Amount = amount
 
-			err := prs.Assessment(transaction)
+			_, err := prs.Assessment(context.Background(), transaction)
 
 			if err != nil {
 				t.Errorf("Expected no error for amount %s, got %v", amount, err)
@@ -395,66 +464,409 @@ func TestPaymentRiskScoring_Assessment_WithDifferentAmounts(t *testing.T) {
 	}
 }
 
-// Benchmark tests
-func BenchmarkPaymentRiskScoring_Assessment(b *testing.B) {
-	logger := zap.NewNop()
+func TestPaymentRiskScoring_Assessment_BudgetExceeded(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	defer logger.Sync()
+
+	mockUTR := &mockUserTransactionsRepository{
+		lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+
+	mockTSC := &mockTransactionScoreCard{}
+
+	prs := NewPaymentRiskScoring(mockUTR, mockTSC, nil, &ScoringConfig{Budget: 10 * time.Millisecond, BaselineMonths: 3}, logger)
+	transaction := createValidTransactionAnalysis()
+
+	_, err := prs.Assessment(context.Background(), transaction)
+
+	var budgetErr errors.ScoringBudgetExceeded
+	if !stderrors.As(err, &budgetErr) {
+		t.Fatalf("Expected ScoringBudgetExceeded error, got %T", err)
+	}
+
+	var lastOrderErr errors.LastOrderNotFound
+	if !stderrors.As(err, &lastOrderErr) {
+		t.Errorf("Expected budget error to wrap LastOrderNotFound, got %v", budgetErr.Err)
+	}
+}
+
+func TestPaymentRiskScoring_Assessment_PropagatesContext(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	defer logger.Sync()
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "checkout")
+	var seen atomic.Int32
+	check := func(ctx context.Context) {
+		if ctx.Value(ctxKey{}) != "checkout" {
+			t.Error("Expected caller context to be propagated")
+		}
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("Expected scoring budget deadline to be set")
+		}
+		seen.Add(1)
+	}
 
 	mockUTR := &mockUserTransactionsRepository{
-		lastOrderFunc: func(document string) (*domain.TransactionAnalysis, error) {
-			return createValidTransactionAnalysis(), nil
+		lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+			check(ctx)
+			return createLastOrder(), nil
 		},
-		averageTransactionsFunc: func(document string, date time.Time) (*domain.UserMonthAverage, error) {
-			return &domain.UserMonthAverage{
-				Document: document,
-				Month:    "2024-01",
-				Total:    "1000.00",
-				Count:    10,
-			}, nil
+		averageBaselineFunc: func(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+			check(ctx)
+			if months != 3 {
+				t.Errorf("Expected a 3 month baseline, got %d", months)
+			}
+			return createAverageBaseline(), nil
 		},
 	}
 
 	mockTSC := &mockTransactionScoreCard{
-		storeFunc: func(scoreCard *domain.ScoringResult) error {
+		storeFunc: func(ctx context.Context, scoreCard *domain.ScoringResult) error {
+			check(ctx)
 			return nil
 		},
 	}
 
-	prs := NewPaymentRiskScoring(mockUTR, mockTSC, logger)
-	transaction := createValidTransactionAnalysis()
+	prs := NewPaymentRiskScoring(mockUTR, mockTSC, nil, createScoringConfig(), logger)
 
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		err := prs.Assessment(transaction)
-		if err != nil {
-			b.Errorf("Unexpected error: %v", err)
+	if _, err := prs.Assessment(ctx, createValidTransactionAnalysis()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if seen.Load() != 3 {
+		t.Errorf("Expected context to reach 3 dependencies, got %d", seen.Load())
+	}
+}
+
+func TestPaymentRiskScoring_Assessment_FetchesHistoryConcurrently(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	defer logger.Sync()
+
+	started := make(chan struct{}, 2)
+	// Each lookup only returns once both have started, so a sequential fetch would time out.
+	waitForSibling := func(ctx context.Context) error {
+		started <- struct{}{}
+		timeout := time.After(time.Second)
+		for len(started) < 2 {
+			select {
+			case <-timeout:
+				return stderrors.New("sibling lookup never started")
+			case <-time.After(time.Millisecond):
+			}
+		}
+		return nil
+	}
+
+	mockUTR := &mockUserTransactionsRepository{
+		lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+			if err := waitForSibling(ctx); err != nil {
+				return nil, err
+			}
+			return createLastOrder(), nil
+		},
+		averageBaselineFunc: func(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+			if err := waitForSibling(ctx); err != nil {
+				return nil, err
+			}
+			return createAverageBaseline(), nil
+		},
+	}
+
+	prs := NewPaymentRiskScoring(mockUTR, &mockTransactionScoreCard{}, nil, &ScoringConfig{Budget: 5 * time.Second, BaselineMonths: 3}, logger)
+
+	if _, err := prs.Assessment(context.Background(), createValidTransactionAnalysis()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestPaymentRiskScoring_Assessment_CancelsSiblingLookup(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	defer logger.Sync()
+
+	cancelled := make(chan error, 1)
+	mockUTR := &mockUserTransactionsRepository{
+		lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+			return nil, stderrors.New("database error")
+		},
+		averageBaselineFunc: func(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+			<-ctx.Done()
+			cancelled <- ctx.Err()
+			return nil, ctx.Err()
+		},
+	}
+
+	stored := false
+	mockTSC := &mockTransactionScoreCard{
+		storeFunc: func(ctx context.Context, scoreCard *domain.ScoringResult) error {
+			stored = true
+			return nil
+		},
+	}
+
+	prs := NewPaymentRiskScoring(mockUTR, mockTSC, nil, &ScoringConfig{Budget: 5 * time.Second, BaselineMonths: 3}, logger)
+
+	_, err := prs.Assessment(context.Background(), createValidTransactionAnalysis())
+
+	if _, ok := err.(errors.LastOrderNotFound); !ok {
+		t.Errorf("Expected LastOrderNotFound error, got %T", err)
+	}
+	select {
+	case ctxErr := <-cancelled:
+		if !stderrors.Is(ctxErr, context.Canceled) {
+			t.Errorf("Expected sibling lookup to be cancelled, got %v", ctxErr)
 		}
+	default:
+		t.Error("Expected sibling lookup to observe the cancellation")
+	}
+	if stored {
+		t.Error("Expected no scorecard to be stored")
+	}
+}
+
+type mockScoringResults struct {
+	cards map[string]repositories.ScoredTransaction
+}
+
+func (m *mockScoringResults) Claim(_ context.Context, key, fingerprint string) (*repositories.ScoredTransaction, bool, error) {
+	if held, ok := m.cards[key]; ok {
+		return &held, false, nil
 	}
+	m.cards[key] = repositories.ScoredTransaction{Fingerprint: fingerprint}
+	return nil, true, nil
 }
 
-// Additional required types and functions for testing
-type errors struct{}
+func (m *mockScoringResults) Put(_ context.Context, key string, card *domain.ScoringResult) error {
+	m.cards[key] = repositories.ScoredTransaction{Fingerprint: card.Transaction.Fingerprint(), Card: card}
+	return nil
+}
 
-func (e errors) New(message string) error {
-	return &customError{message: message}
+func (m *mockScoringResults) Release(_ context.Context, key string) error {
+	delete(m.cards, key)
+	return nil
 }
 
-type customError struct {
-	message string
+func TestPaymentRiskScoring_Assessment_Duplicates(t *testing.T) {
+	tests := []struct {
+		duplicates string
+		stores     int32
+	}{
+		{duplicates: DuplicatesSkip, stores: 1},
+		{duplicates: DuplicatesReemit, stores: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.duplicates, func(t *testing.T) {
+			var lookups, stores atomic.Int32
+			mockUTR := &mockUserTransactionsRepository{
+				lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+					lookups.Add(1)
+					return createLastOrder(), nil
+				},
+				averageBaselineFunc: func(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+					return createAverageBaseline(), nil
+				},
+			}
+			mockTSC := &mockTransactionScoreCard{
+				storeFunc: func(ctx context.Context, scoreCard *domain.ScoringResult) error {
+					stores.Add(1)
+					return nil
+				},
+			}
+			config := createScoringConfig()
+			config.Duplicates = tt.duplicates
+			prs := NewPaymentRiskScoring(mockUTR, mockTSC, &mockScoringResults{cards: map[string]repositories.ScoredTransaction{}}, config, zaptest.NewLogger(t))
+
+			transaction := createValidTransactionAnalysis()
+			transaction.Payment.IdempotencyKey = "payment-key"
+			first, err := prs.Assessment(context.Background(), transaction)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			redelivered := *transaction
+			second, err := prs.Assessment(context.Background(), &redelivered)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if second.Transaction.Payment.Id != first.Transaction.Payment.Id || second.Decision != first.Decision {
+				t.Errorf("Expected the original scorecard, got %+v", second)
+			}
+			if lookups.Load() != 1 {
+				t.Errorf("Expected the duplicate not to be scored, got %d lookups", lookups.Load())
+			}
+			if stores.Load() != tt.stores {
+				t.Errorf("Expected %d stores, got %d", tt.stores, stores.Load())
+			}
+		})
+	}
+}
+
+func TestPaymentRiskScoring_Assessment_IdempotencyConflicts(t *testing.T) {
+	mockUTR := &mockUserTransactionsRepository{
+		lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+			return createLastOrder(), nil
+		},
+		averageBaselineFunc: func(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+			return createAverageBaseline(), nil
+		},
+	}
+	results := &mockScoringResults{cards: map[string]repositories.ScoredTransaction{}}
+	prs := NewPaymentRiskScoring(mockUTR, &mockTransactionScoreCard{}, results, createScoringConfig(), zaptest.NewLogger(t))
+
+	transaction := createValidTransactionAnalysis()
+	transaction.Payment.IdempotencyKey = "payment-key"
+	results.cards[transaction.IdempotencyKey()] = repositories.ScoredTransaction{Fingerprint: transaction.Fingerprint()}
+	if _, err := prs.Assessment(context.Background(), transaction); !stderrors.Is(err, errors.ErrScoringInProgress) {
+		t.Errorf("Expected a conflict while the transaction is being scored, got %v", err)
+	}
+
+	delete(results.cards, transaction.IdempotencyKey())
+	if _, err := prs.Assessment(context.Background(), transaction); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	reused := *transaction
+	reused.Payment.Amount = "999.00"
+	_, err := prs.Assessment(context.Background(), &reused)
+	var conflict errors.IdempotencyConflict
+	if !stderrors.As(err, &conflict) || !stderrors.Is(err, errors.ErrIdempotencyKeyReused) {
+		t.Errorf("Expected a conflict for a different transaction reusing the key, got %v", err)
+	}
+}
+
+func TestPaymentRiskScoring_Assessment_ReleasesKeyOfFailedTransaction(t *testing.T) {
+	mockUTR := &mockUserTransactionsRepository{
+		lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+			return nil, stderrors.New("connection refused")
+		},
+		averageBaselineFunc: func(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+			return createAverageBaseline(), nil
+		},
+	}
+	results := &mockScoringResults{cards: map[string]repositories.ScoredTransaction{}}
+	prs := NewPaymentRiskScoring(mockUTR, &mockTransactionScoreCard{}, results, createScoringConfig(), zaptest.NewLogger(t))
+
+	transaction := createValidTransactionAnalysis()
+	transaction.Payment.IdempotencyKey = "payment-key"
+	if _, err := prs.Assessment(context.Background(), transaction); err == nil {
+		t.Fatal("Expected the assessment to fail")
+	}
+	if _, held := results.cards[transaction.IdempotencyKey()]; held {
+		t.Error("Expected the key of a transaction that failed to be released")
+	}
 }
 
-func (e *customError) Error() string {
-	return e.message
+func TestPaymentRiskScoring_VettedAssessment(t *testing.T) {
+	var stores atomic.Int32
+	mockUTR := &mockUserTransactionsRepository{
+		lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+			return createLastOrder(), nil
+		},
+		averageBaselineFunc: func(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+			return createAverageBaseline(), nil
+		},
+	}
+	mockTSC := &mockTransactionScoreCard{
+		storeFunc: func(ctx context.Context, scoreCard *domain.ScoringResult) error {
+			stores.Add(1)
+			return nil
+		},
+	}
+	results := &mockScoringResults{cards: map[string]repositories.ScoredTransaction{}}
+	prs := NewPaymentRiskScoring(mockUTR, mockTSC, results, createScoringConfig(), zaptest.NewLogger(t))
+	var vetted int
+	vet := func(accept bool) Vet {
+		return func(ctx context.Context, order *domain.TransactionAnalysis) error {
+			vetted++
+			if !accept {
+				return errors.TransactionRejected{Errors: []string{"transaction too old"}}
+			}
+			return nil
+		}
+	}
+
+	transaction := createValidTransactionAnalysis()
+	transaction.Payment.IdempotencyKey = "payment-key"
+	if _, err := prs.VettedAssessment(context.Background(), transaction, vet(false)); !stderrors.As(err, &errors.TransactionRejected{}) {
+		t.Fatalf("Expected the transaction to be rejected, got %v", err)
+	}
+	if _, held := results.cards[transaction.IdempotencyKey()]; held || stores.Load() != 0 {
+		t.Fatal("Expected a rejected transaction to be neither scored nor kept")
+	}
+
+	redelivered := *transaction
+	if _, err := prs.VettedAssessment(context.Background(), &redelivered, vet(true)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// Already scored: the scorecard is answered without vetting the transaction again
+	again := *transaction
+	card, err := prs.VettedAssessment(context.Background(), &again, vet(false))
+	if err != nil || card == nil {
+		t.Fatalf("Expected the original scorecard, got %v", err)
+	}
+	if vetted != 2 || stores.Load() != 1 {
+		t.Errorf("Expected the duplicate not to be vetted nor scored, got %d vets and %d stores", vetted, stores.Load())
+	}
 }
 
-// Additional domain types that might be missing
-type UserMonthAverage struct {
-	Document string
-	Month    string
-	Total    string
-	Count    int
+func TestPaymentRiskScoring_Assessment_ScoresTransactionsWithoutKey(t *testing.T) {
+	var stores atomic.Int32
+	mockUTR := &mockUserTransactionsRepository{
+		lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+			return createLastOrder(), nil
+		},
+		averageBaselineFunc: func(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+			return createAverageBaseline(), nil
+		},
+	}
+	mockTSC := &mockTransactionScoreCard{
+		storeFunc: func(ctx context.Context, scoreCard *domain.ScoringResult) error {
+			stores.Add(1)
+			return nil
+		},
+	}
+	results := &mockScoringResults{cards: map[string]repositories.ScoredTransaction{}}
+	prs := NewPaymentRiskScoring(mockUTR, mockTSC, results, createScoringConfig(), zaptest.NewLogger(t))
+
+	for i := 0; i < 2; i++ {
+		if _, err := prs.Assessment(context.Background(), createValidTransactionAnalysis()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if stores.Load() != 2 || len(results.cards) != 0 {
+		t.Errorf("Expected transactions without idempotency key to be scored every time, got %d stores", stores.Load())
+	}
 }
 
-// Add this to domain package if not exists
-func (d *domain) UserMonthAverage() *UserMonthAverage {
-	return &UserMonthAverage{}
-}
\ No newline at end of file
+// Benchmark tests
+func BenchmarkPaymentRiskScoring_Assessment(b *testing.B) {
+	logger := zap.NewNop()
+
+	mockUTR := &mockUserTransactionsRepository{
+		lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+			return createLastOrder(), nil
+		},
+		averageBaselineFunc: func(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+			return createAverageBaseline(), nil
+		},
+	}
+
+	mockTSC := &mockTransactionScoreCard{
+		storeFunc: func(ctx context.Context, scoreCard *domain.ScoringResult) error {
+			return nil
+		},
+	}
+
+	prs := NewPaymentRiskScoring(mockUTR, mockTSC, nil, createScoringConfig(), logger)
+	transaction := createValidTransactionAnalysis()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := prs.Assessment(context.Background(), transaction)
+		if err != nil {
+			b.Errorf("Unexpected error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"fraud-scoring/internal/domain/history"
+	"fraud-scoring/internal/domain/repositories"
+	"sync"
+	"time"
+)
+
+// sharedHistory serves the history lookups of a batch. The first item asking for a lookup fetches it, the items
+// asking for the same one meanwhile or later get its answer. A failed lookup is forgotten, so the next item retries.
+type sharedHistory struct {
+	utr     repositories.UserTransactionsRepository
+	mu      sync.Mutex
+	lookups map[string]*sharedLookup
+}
+
+type sharedLookup struct {
+	done chan struct{}
+	val  any
+	err  error
+}
+
+func (sh *sharedHistory) LastOrder(ctx context.Context, document string) (*history.LastOrder, error) {
+	return share(ctx, sh, "last_order:"+document, func(ctx context.Context) (*history.LastOrder, error) {
+		return sh.utr.LastOrder(ctx, document)
+	})
+}
+
+func (sh *sharedHistory) AverageTransactions(ctx context.Context, document string, date time.Time) (*history.AveragePayment, error) {
+	key := fmt.Sprintf("average:%s:%s", document, date.Format(history.MonthLayout))
+	return share(ctx, sh, key, func(ctx context.Context) (*history.AveragePayment, error) {
+		return sh.utr.AverageTransactions(ctx, document, date)
+	})
+}
+
+func (sh *sharedHistory) AverageBaseline(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+	key := fmt.Sprintf("average_baseline:%s:%s:%d", document, date.Format(history.MonthLayout), months)
+	return share(ctx, sh, key, func(ctx context.Context) (*history.AverageBaseline, error) {
+		return sh.utr.AverageBaseline(ctx, document, date, months)
+	})
+}
+
+func share[T any](ctx context.Context, sh *sharedHistory, key string, fetch func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	sh.mu.Lock()
+	if l, ok := sh.lookups[key]; ok {
+		sh.mu.Unlock()
+		select {
+		case <-l.done:
+			if l.err != nil {
+				return zero, l.err
+			}
+			return l.val.(T), nil
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+	l := &sharedLookup{done: make(chan struct{})}
+	sh.lookups[key] = l
+	sh.mu.Unlock()
+
+	val, err := fetch(ctx)
+	l.val, l.err = val, err
+	if err != nil {
+		sh.mu.Lock()
+		delete(sh.lookups, key)
+		sh.mu.Unlock()
+	}
+	close(l.done)
+	return val, err
+}
+
+func newSharedHistory(utr repositories.UserTransactionsRepository) *sharedHistory {
+	return &sharedHistory{utr: utr, lookups: map[string]*sharedLookup{}}
+}
@@ -0,0 +1,55 @@
+package application
+
+import (
+	"context"
+	"fraud-scoring/internal/domain/history"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestPaymentRiskScoring_Assessment_Spans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	mockUTR := &mockUserTransactionsRepository{
+		lastOrderFunc: func(ctx context.Context, document string) (*history.LastOrder, error) {
+			return createLastOrder(), nil
+		},
+		averageBaselineFunc: func(ctx context.Context, document string, date time.Time, months int) (*history.AverageBaseline, error) {
+			return createAverageBaseline(), nil
+		},
+	}
+	prs := NewPaymentRiskScoring(mockUTR, &mockTransactionScoreCard{}, nil, createScoringConfig(), zaptest.NewLogger(t))
+	if _, err := prs.Assessment(context.Background(), createValidTransactionAnalysis()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	var assessment tracetest.SpanStub
+	for _, s := range spans {
+		if s.Name == "PaymentRiskScoring.Assessment" {
+			assessment = s
+		}
+	}
+	if !assessment.SpanContext.IsValid() {
+		t.Fatalf("Expected an assessment span, got %d spans", len(spans))
+	}
+	criteria := map[string]bool{}
+	for _, s := range spans {
+		if s.Parent.SpanID() == assessment.SpanContext.SpanID() {
+			criteria[s.Name] = true
+		}
+	}
+	for _, name := range []string{"criteria.value", "criteria.currency", "criteria.seller", "criteria.average_value"} {
+		if !criteria[name] {
+			t.Errorf("Expected a %s span under the assessment, got %v", name, criteria)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"fraud-scoring/internal/domain"
+	"fraud-scoring/internal/domain/application/errors"
+	"fraud-scoring/internal/domain/repositories"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// TransactionValidation checks the transactions read from the payment events before they are scored. A transaction
+// breaking the rules of the transaction component is rejected instead: its rejection is published with the rules it
+// breaks, rather than scoring it on values that make no sense.
+type TransactionValidation struct {
+	tc         *domain.TransactionComponent
+	rejections repositories.TransactionRejections
+	log        *zap.Logger
+}
+
+// Validate returns whether transaction can be scored, its age measured at receivedAt: when it was first received, so a
+// redelivery is judged as the first delivery was. When it can't, its rejection is published first, a RejectionFailed
+// means it was not and the transaction should be validated again.
+func (tv *TransactionValidation) Validate(ctx context.Context, transaction *domain.TransactionAnalysis, receivedAt time.Time) (*domain.ComponentValidationResult, error) {
+	result, err := tv.tc.ComponentAt(transaction, receivedAt)
+	if err != nil || result.IsValid {
+		return result, err
+	}
+	tv.log.Warn("rejecting transaction",
+		zap.String("id", transaction.Payment.Id),
+		zap.Strings("errors", result.Errors))
+	rejection := &domain.TransactionRejection{Transaction: *transaction, Errors: result.Errors}
+	if err := tv.rejections.Store(ctx, rejection); err != nil {
+		return nil, errors.RejectionFailed{Err: fmt.Errorf("transaction %s: %w", transaction.Payment.Id, err)}
+	}
+	return result, nil
+}
+
+func NewTransactionValidation(tc *domain.TransactionComponent, rejections repositories.TransactionRejections, log *zap.Logger) *TransactionValidation {
+	return &TransactionValidation{tc: tc, rejections: rejections, log: log}
+}
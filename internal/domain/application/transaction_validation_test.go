@@ -0,0 +1,88 @@
+package application
+
+import (
+	"context"
+	stderrors "errors"
+	"fraud-scoring/internal/domain"
+	"fraud-scoring/internal/domain/application/errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+type mockTransactionRejections struct {
+	stored []*domain.TransactionRejection
+	err    error
+}
+
+func (m *mockTransactionRejections) Store(_ context.Context, rejection *domain.TransactionRejection) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.stored = append(m.stored, rejection)
+	return nil
+}
+
+func createTransactionComponent() *domain.TransactionComponent {
+	return domain.NewTransactionComponent(24*time.Hour, 0.01, 1000000, []string{"BRL", "USD", "EUR"})
+}
+
+func TestTransactionValidation_Validate(t *testing.T) {
+	rejections := &mockTransactionRejections{}
+	tv := NewTransactionValidation(createTransactionComponent(), rejections, zaptest.NewLogger(t))
+
+	result, err := tv.Validate(context.Background(), createValidTransactionAnalysis(), time.Now())
+	if err != nil || !result.IsValid {
+		t.Fatalf("Expected a valid transaction, got %+v, %v", result, err)
+	}
+	if len(rejections.stored) != 0 {
+		t.Fatalf("Expected no rejection for a valid transaction, got %d", len(rejections.stored))
+	}
+
+	transaction := createValidTransactionAnalysis()
+	transaction.Payment.Amount = "abc"
+	transaction.Payment.Currency = "XYZ"
+	result, err = tv.Validate(context.Background(), transaction, time.Now())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.IsValid {
+		t.Fatal("Expected the transaction to be invalid")
+	}
+	if len(rejections.stored) != 1 {
+		t.Fatalf("Expected the rejection to be published, got %d", len(rejections.stored))
+	}
+	rejection := rejections.stored[0]
+	if rejection.Transaction.Payment.Id != transaction.Payment.Id || len(rejection.Errors) != len(result.Errors) {
+		t.Errorf("Expected the rejection to carry the transaction and its errors, got %+v", rejection)
+	}
+}
+
+func TestTransactionValidation_Validate_RejectionNotPublished(t *testing.T) {
+	rejections := &mockTransactionRejections{err: stderrors.New("kafka: client has run out of available brokers")}
+	tv := NewTransactionValidation(createTransactionComponent(), rejections, zaptest.NewLogger(t))
+
+	transaction := createValidTransactionAnalysis()
+	transaction.Payment.Amount = "abc"
+	_, err := tv.Validate(context.Background(), transaction, time.Now())
+	if !stderrors.Is(err, rejections.err) || !stderrors.As(err, &errors.RejectionFailed{}) {
+		t.Errorf("Expected the publishing error, got %v", err)
+	}
+}
+
+func TestTransactionValidation_Validate_MeasuresAgeAtReception(t *testing.T) {
+	rejections := &mockTransactionRejections{}
+	tv := NewTransactionValidation(createTransactionComponent(), rejections, zaptest.NewLogger(t))
+
+	transaction := createValidTransactionAnalysis()
+	transaction.Order.At = time.Now().Add(-72 * time.Hour)
+	result, err := tv.Validate(context.Background(), transaction, transaction.Order.At.Add(time.Minute))
+	if err != nil || !result.IsValid {
+		t.Fatalf("Expected a transaction received on time to be valid, got %+v, %v", result, err)
+	}
+	result, err = tv.Validate(context.Background(), transaction, time.Now())
+	if err != nil || result.IsValid {
+		t.Fatalf("Expected a transaction received late to be too old, got %+v, %v", result, err)
+	}
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 type CheckoutData struct {
 	Checkout struct {
 		Id        string `json:"id"`
@@ -25,3 +27,50 @@ type CheckoutData struct {
 		IdempotencyKey string `json:"idempotencyKey"`
 	} `json:"payment"`
 }
+
+// TransactionAnalysis maps the checkout to the transaction scored by the risk assessment. Checkout.At is read by times;
+// when it is missing or unreadable the checkout is taken to have happened at fallback, the time of the event carrying
+// it, unless fallback is zero too. The checkout time records which of the two was used.
+func (cd *CheckoutData) TransactionAnalysis(times *CheckoutTimeParser, fallback time.Time) (*TransactionAnalysis, error) {
+	at, source, err := cd.checkoutTime(times, fallback)
+	if err != nil {
+		return nil, err
+	}
+	return &TransactionAnalysis{
+		Participants: Participants{
+			Buyer: BuyerInfo{
+				Document: cd.Checkout.BuyerInfo.Document,
+				Name:     cd.Checkout.BuyerInfo.Name,
+			},
+			Seller: SellerInfo{SellerId: cd.Payment.SellerInfo.SellerId},
+		},
+		Order: Checkout{
+			Id: cd.Checkout.Id,
+			PaymentType: CardInfo{
+				CardInfo: cd.Checkout.CardInfo.CardInfo,
+				Token:    cd.Checkout.CardInfo.Token,
+			},
+			At:             at,
+			AtSource:       source,
+			IdempotencyKey: cd.Checkout.IdempotencyKey,
+		},
+		Payment: Payment{
+			Amount:         cd.Payment.Amount,
+			Currency:       cd.Payment.Currency,
+			Status:         cd.Payment.Status,
+			Id:             cd.Payment.Id,
+			IdempotencyKey: cd.Payment.IdempotencyKey,
+		},
+	}, nil
+}
+
+func (cd *CheckoutData) checkoutTime(times *CheckoutTimeParser, fallback time.Time) (time.Time, string, error) {
+	at, err := times.Parse(cd.Checkout.At)
+	if err == nil {
+		return at, CheckoutTimeFromCheckout, nil
+	}
+	if fallback.IsZero() {
+		return time.Time{}, "", err
+	}
+	return fallback.In(times.Location), CheckoutTimeFromEvent, nil
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckoutData_TransactionAnalysis(t *testing.T) {
+	data := &CheckoutData{}
+	data.Checkout.Id = "checkout-123"
+	data.Checkout.BuyerInfo.Document = "12345678901"
+	data.Checkout.CardInfo.Token = "tok_123"
+	data.Checkout.At = "2024-01-15T10:30:00.123456"
+	data.Payment.Id = "payment-123"
+	data.Payment.Amount = "100.00"
+	data.Payment.SellerInfo.SellerId = "seller-123"
+	data.Checkout.IdempotencyKey = "checkout-key"
+	data.Payment.IdempotencyKey = "payment-key"
+
+	times := NewCheckoutTimeParser([]string{"2006-01-02T15:04:05"}, time.UTC)
+	analysis, err := data.TransactionAnalysis(times, time.Time{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !analysis.Order.At.Equal(time.Date(2024, time.January, 15, 10, 30, 0, 123456000, time.UTC)) {
+		t.Errorf("Unexpected checkout time %s", analysis.Order.At)
+	}
+	if analysis.Order.AtSource != CheckoutTimeFromCheckout {
+		t.Errorf("Expected the time of the checkout, got %q", analysis.Order.AtSource)
+	}
+	if analysis.Participants.Seller.SellerId != "seller-123" || analysis.Order.PaymentType.Token != "tok_123" {
+		t.Errorf("Unexpected analysis %+v", analysis)
+	}
+	if analysis.IdempotencyKey() != "checkout-key/payment-key" {
+		t.Errorf("Expected the idempotency keys to be kept, got %q", analysis.IdempotencyKey())
+	}
+
+	data.Checkout.At = "15/01/2024"
+	if _, err := data.TransactionAnalysis(times, time.Time{}); err == nil {
+		t.Error("Expected error for a malformed checkout time")
+	}
+}
+
+func TestCheckoutData_TransactionAnalysis_FallsBackToEventTime(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Skipf("Time zone database unavailable: %v", err)
+	}
+	times := NewCheckoutTimeParser([]string{"2006-01-02T15:04:05"}, saoPaulo)
+	eventTime := time.Date(2024, time.February, 1, 1, 0, 0, 0, time.UTC)
+
+	for _, at := range []string{"", "15/01/2024"} {
+		data := &CheckoutData{}
+		data.Checkout.At = at
+		analysis, err := data.TransactionAnalysis(times, eventTime)
+		if err != nil {
+			t.Fatalf("Expected the event time to be used for %q, got %v", at, err)
+		}
+		if !analysis.Order.At.Equal(eventTime) || analysis.Order.AtSource != CheckoutTimeFromEvent {
+			t.Errorf("Expected the event time for %q, got %s from %q", at, analysis.Order.At, analysis.Order.AtSource)
+		}
+		// The event time is in the configured zone, still January there
+		if analysis.Order.At.Month() != time.January {
+			t.Errorf("Expected the checkout in January in São Paulo, got %s", analysis.Order.At)
+		}
+	}
+}
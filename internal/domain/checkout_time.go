@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Where the checkout time of a transaction was read from.
+const (
+	CheckoutTimeFromCheckout = "checkout"
+	CheckoutTimeFromEvent    = "event_time"
+)
+
+var ErrNoCheckoutTime = errors.New("checkout time is empty")
+
+// CheckoutTimeParser reads the checkout times sent by the payment processing. Times with an offset, RFC 3339 ones, and
+// epoch milliseconds are read as they are; times without zone, in one of Layouts, are read in Location. The times are
+// returned in Location, whatever their offset, so a transaction falls in the same month however it was sent.
+type CheckoutTimeParser struct {
+	Layouts  []string
+	Location *time.Location
+}
+
+// Parse reads value, failing when no accepted format matches. Any precision of fractional seconds is accepted.
+func (ctp *CheckoutTimeParser) Parse(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, ErrNoCheckoutTime
+	}
+	if millis, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.UnixMilli(millis).In(ctp.Location), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t.In(ctp.Location), nil
+	}
+	for _, layout := range ctp.Layouts {
+		if t, err := time.ParseInLocation(layout, value, ctp.Location); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("checkout time %q is neither RFC 3339, epoch milliseconds nor in one of the layouts %q", value, ctp.Layouts)
+}
+
+// NewCheckoutTimeParser accepts layouts as zone-less times read in location, UTC when it is nil.
+func NewCheckoutTimeParser(layouts []string, location *time.Location) *CheckoutTimeParser {
+	if location == nil {
+		location = time.UTC
+	}
+	return &CheckoutTimeParser{Layouts: layouts, Location: location}
+}
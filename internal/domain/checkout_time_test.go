@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckoutTimeParser_Parse(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Skipf("Time zone database unavailable: %v", err)
+	}
+	parser := NewCheckoutTimeParser([]string{"2006-01-02T15:04:05", "2006-01-02 15:04:05"}, saoPaulo)
+
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Time
+	}{
+		{"Microseconds without zone", "2024-01-15T10:30:00.123456", time.Date(2024, time.January, 15, 10, 30, 0, 123456000, saoPaulo)},
+		{"Seconds without zone", "2024-01-15T10:30:00", time.Date(2024, time.January, 15, 10, 30, 0, 0, saoPaulo)},
+		{"Space separated", "2024-01-15 10:30:00.5", time.Date(2024, time.January, 15, 10, 30, 0, 500000000, saoPaulo)},
+		{"RFC 3339 in UTC", "2024-01-15T13:30:00Z", time.Date(2024, time.January, 15, 13, 30, 0, 0, time.UTC)},
+		{"RFC 3339 with offset and nanoseconds", "2024-01-15T10:30:00.123456789+01:00", time.Date(2024, time.January, 15, 9, 30, 0, 123456789, time.UTC)},
+		{"Epoch milliseconds", "1705325400123", time.Date(2024, time.January, 15, 13, 30, 0, 123000000, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at, err := parser.Parse(tt.value)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !at.Equal(tt.expected) {
+				t.Errorf("Expected %s, got %s", tt.expected, at)
+			}
+			if at.Location() != saoPaulo {
+				t.Errorf("Expected the time in %s, got %s", saoPaulo, at.Location())
+			}
+		})
+	}
+
+	for _, value := range []string{"", "15/01/2024", "2024-01-15T10:30"} {
+		if _, err := parser.Parse(value); err == nil {
+			t.Errorf("Expected error for %q", value)
+		}
+	}
+}
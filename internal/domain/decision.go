@@ -0,0 +1,42 @@
+package domain
+
+type Decision string
+
+const (
+	DecisionApprove Decision = "approve"
+	DecisionReview  Decision = "review"
+	DecisionDecline Decision = "decline"
+)
+
+// DecisionPolicy turns a scorecard into a decision. Criteria subtract points for every risk signal, so the lower the
+// total the riskier the transaction.
+type DecisionPolicy struct {
+	// ReviewAt is the total at or below which a transaction goes to manual review.
+	ReviewAt int
+	// DeclineAt is the total at or below which a transaction is declined.
+	DeclineAt int
+}
+
+func (dp DecisionPolicy) Decide(card ScoreCard) Decision {
+	total := card.Total()
+	switch {
+	case total <= dp.DeclineAt:
+		return DecisionDecline
+	case total <= dp.ReviewAt:
+		return DecisionReview
+	default:
+		return DecisionApprove
+	}
+}
+
+// RiskLevel is the risk classification reported along with the decision.
+func (d Decision) RiskLevel() string {
+	switch d {
+	case DecisionDecline:
+		return "high"
+	case DecisionReview:
+		return "medium"
+	default:
+		return "low"
+	}
+}
@@ -0,0 +1,55 @@
+package domain
+
+import "testing"
+
+func TestDecisionPolicy_Decide(t *testing.T) {
+	policy := DecisionPolicy{ReviewAt: -3, DeclineAt: -6}
+
+	tests := []struct {
+		name     string
+		card     ScoreCard
+		expected Decision
+	}{
+		{
+			name:     "No risk signals",
+			card:     ScoreCard{},
+			expected: DecisionApprove,
+		},
+		{
+			name:     "Single minor signal",
+			card:     ScoreCard{CurrencyScore: CurrencyScoreCard{Score: -1}},
+			expected: DecisionApprove,
+		},
+		{
+			name:     "At review threshold",
+			card:     ScoreCard{ValueScore: ValueScoreCard{Score: -3}},
+			expected: DecisionReview,
+		},
+		{
+			name: "At decline threshold",
+			card: ScoreCard{
+				ValueScore:        ValueScoreCard{Score: -3},
+				AverageValueScore: AverageValueScoreCard{Score: -3},
+			},
+			expected: DecisionDecline,
+		},
+		{
+			name: "Every signal",
+			card: ScoreCard{
+				ValueScore:        ValueScoreCard{Score: -3},
+				SellerScore:       SellerScoreCard{Score: -1},
+				AverageValueScore: AverageValueScoreCard{Score: -3},
+				CurrencyScore:     CurrencyScoreCard{Score: -1},
+			},
+			expected: DecisionDecline,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Decide(tt.card); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package history
+
+import (
+	"errors"
+	"strconv"
+)
+
+// MonthLayout is the YYYY-MM encoding of a month used by the user transactions API.
+const MonthLayout = "2006-01"
+
+// ErrNoMonthlyAverages is returned by NewAverageBaseline when none of the months had transactions.
+var ErrNoMonthlyAverages = errors.New("no monthly averages to build a baseline from")
+
+// AverageBaseline is the usual transaction value of a buyer, built from the monthly averages of the months before a
+// transaction instead of the current, partial one.
+type AverageBaseline struct {
+	// Months holds the monthly averages used, the most recent first.
+	Months     []AveragePayment
+	Amount     string
+	Provenance Provenance
+}
+
+// NewAverageBaseline weights the monthly averages by recency. samples[i] is the average of i+1 months ago, so out of n
+// samples the most recent weighs n and the oldest weighs 1. Nil samples are months without transactions and are skipped.
+func NewAverageBaseline(samples []*AveragePayment) (*AverageBaseline, error) {
+	baseline := &AverageBaseline{}
+	var sum, weights float64
+	for i, sample := range samples {
+		if sample == nil {
+			continue
+		}
+		amount, err := strconv.ParseFloat(sample.Amount, 64)
+		if err != nil {
+			return nil, errors.New("invalid average amount for month " + sample.Month + ": " + sample.Amount)
+		}
+		weight := float64(len(samples) - i)
+		sum += amount * weight
+		weights += weight
+		baseline.Months = append(baseline.Months, *sample)
+	}
+	if weights == 0 {
+		return nil, ErrNoMonthlyAverages
+	}
+	baseline.Amount = strconv.FormatFloat(sum/weights, 'f', 2, 64)
+	return baseline, nil
+}
+
+// Empty reports whether no monthly average backs the baseline: the buyer has no history to compare the transaction
+// with, and the average value criterion doesn't apply.
+func (ab *AverageBaseline) Empty() bool {
+	return ab.Amount == ""
+}
@@ -0,0 +1,73 @@
+package history
+
+import "testing"
+
+func TestNewAverageBaseline(t *testing.T) {
+	tests := []struct {
+		name           string
+		samples        []*AveragePayment
+		expectedAmount string
+		expectedMonths int
+	}{
+		{
+			name:           "Single month",
+			samples:        []*AveragePayment{{Month: "2024-02", Amount: "150.00"}},
+			expectedAmount: "150.00",
+			expectedMonths: 1,
+		},
+		{
+			name: "Recent months weigh more",
+			samples: []*AveragePayment{
+				{Month: "2024-02", Amount: "300.00"},
+				{Month: "2024-01", Amount: "150.00"},
+				{Month: "2023-12", Amount: "0"},
+			},
+			expectedAmount: "200.00", // (300*3 + 150*2 + 0*1) / 6
+			expectedMonths: 3,
+		},
+		{
+			name: "Months without transactions are skipped",
+			samples: []*AveragePayment{
+				{Month: "2024-02", Amount: "100.00"},
+				nil,
+				{Month: "2023-12", Amount: "500.00"},
+			},
+			expectedAmount: "200.00", // (100*3 + 500*1) / 4
+			expectedMonths: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseline, err := NewAverageBaseline(tt.samples)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if baseline.Amount != tt.expectedAmount {
+				t.Errorf("Expected amount %s, got %s", tt.expectedAmount, baseline.Amount)
+			}
+			if len(baseline.Months) != tt.expectedMonths {
+				t.Errorf("Expected %d months, got %d", tt.expectedMonths, len(baseline.Months))
+			}
+		})
+	}
+}
+
+func TestNewAverageBaseline_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []*AveragePayment
+	}{
+		{name: "No samples", samples: nil},
+		{name: "Only empty months", samples: []*AveragePayment{nil, nil}},
+		{name: "Invalid amount", samples: []*AveragePayment{{Month: "2024-02", Amount: "abc"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAverageBaseline(tt.samples); err == nil {
+				t.Error("Expected error, got none")
+			}
+		})
+	}
+}
@@ -3,4 +3,8 @@ package history
 type AveragePayment struct {
 	Month  string
 	Amount string
+	// Total and Count are the sum and number of the transactions of the month, when the provider knows them.
+	Total      string
+	Count      int
+	Provenance Provenance
 }
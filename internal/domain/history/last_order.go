@@ -1,7 +1,17 @@
 package history
 
+import "time"
+
 type LastOrder struct {
 	SellerId string
 	Currency string
 	Amount   string
+	// At is when the order was placed, zero when the provider doesn't know it.
+	At         time.Time
+	Provenance Provenance
+}
+
+// Empty reports whether the buyer has no previous order, the criteria comparing with it don't apply.
+func (lo *LastOrder) Empty() bool {
+	return lo.SellerId == "" && lo.Currency == "" && lo.Amount == ""
 }
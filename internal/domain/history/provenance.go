@@ -0,0 +1,16 @@
+package history
+
+import "time"
+
+const (
+	SourceGrpc    = "grpc"
+	SourceCache   = "cache"
+	SourceLocal   = "local"
+	SourceDefault = "default"
+)
+
+// Provenance tells which history provider answered and how fresh the answer is.
+type Provenance struct {
+	Source string    `json:"source"`
+	AsOf   time.Time `json:"asOf"`
+}
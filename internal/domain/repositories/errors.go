@@ -0,0 +1,14 @@
+package repositories
+
+// HistoryNotFound is returned when the buyer has no history for a lookup, as opposed to the history being unavailable.
+type HistoryNotFound struct {
+	Err error
+}
+
+func (hnf HistoryNotFound) Error() string {
+	return "history not found: " + hnf.Err.Error()
+}
+
+func (hnf HistoryNotFound) Unwrap() error {
+	return hnf.Err
+}
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+	"fraud-scoring/internal/domain"
+)
+
+// ScoredTransaction is what ScoringResults keeps per idempotency key: the fingerprint of the transaction that claimed
+// the key and, once stored, its scorecard.
+type ScoredTransaction struct {
+	Fingerprint string
+	Card        *domain.ScoringResult
+}
+
+// ScoringResults remembers the scorecards already stored, by the idempotency key of their transaction, so a transaction
+// delivered again is not scored twice.
+type ScoringResults interface {
+	// Claim holds key for the transaction with fingerprint, unless it is already held and not expired. It returns false
+	// with the transaction holding it then, whose Card is nil while it is being scored. Checking and holding the key are
+	// atomic, two deliveries of a transaction can't both claim it.
+	Claim(ctx context.Context, key, fingerprint string) (*ScoredTransaction, bool, error)
+	// Put stores the scorecard of the transaction holding key.
+	Put(ctx context.Context, key string, card *domain.ScoringResult) error
+	// Release frees key when its transaction could not be scored, so it is scored again when delivered again.
+	Release(ctx context.Context, key string) error
+}
@@ -0,0 +1,11 @@
+package repositories
+
+import (
+	"context"
+	"fraud-scoring/internal/domain"
+)
+
+// TransactionRejections tells the downstream bounded contexts about the transactions that were not scored.
+type TransactionRejections interface {
+	Store(ctx context.Context, rejection *domain.TransactionRejection) error
+}
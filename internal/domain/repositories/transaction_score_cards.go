@@ -1,7 +1,10 @@
 package repositories
 
-import "fraud-scoring/internal/domain"
+import (
+	"context"
+	"fraud-scoring/internal/domain"
+)
 
 type TransactionScoreCard interface {
-	Store(card *domain.ScoringResult) error
+	Store(ctx context.Context, card *domain.ScoringResult) error
 }
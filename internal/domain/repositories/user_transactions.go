@@ -1,11 +1,14 @@
 package repositories
 
 import (
+	"context"
 	"fraud-scoring/internal/domain/history"
 	"time"
 )
 
 type UserTransactionsRepository interface {
-	LastOrder(document string) (*history.LastOrder, error)
-	AverageTransactions(document string, at time.Time) (*history.AveragePayment, error)
+	LastOrder(ctx context.Context, document string) (*history.LastOrder, error)
+	AverageTransactions(ctx context.Context, document string, at time.Time) (*history.AveragePayment, error)
+	// AverageBaseline builds the buyer's baseline from the averages of the given number of months before at.
+	AverageBaseline(ctx context.Context, document string, at time.Time, months int) (*history.AverageBaseline, error)
 }
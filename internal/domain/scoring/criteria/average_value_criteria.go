@@ -1,13 +1,16 @@
 package criteria
 
-import "fraud-scoring/internal/domain/scoring"
+import (
+	"fraud-scoring/internal/domain/scoring"
+	"strconv"
+)
 
 type AverageValueCriteria struct {
 	Next scoring.Rule
 }
 
 func (a *AverageValueCriteria) Execute(input scoring.TransactionRiskScoreInput, factors *scoring.TransactionRiskFactors) {
-	if input.Transaction.Payment.Amount >= input.Average.Amount {
+	if aboveBaseline(input) {
 		factors.WithAverageValueScore(scoring.AverageValueRiskScoreEvaluation{Scoring: -3})
 	} else {
 		factors.WithAverageValueScore(scoring.AverageValueRiskScoreEvaluation{Scoring: 0})
@@ -16,3 +19,20 @@ func (a *AverageValueCriteria) Execute(input scoring.TransactionRiskScoreInput,
 		a.Next.Execute(input, factors)
 	}
 }
+
+// aboveBaseline compares amounts as numbers, "90.00" would be above "1000.00" as strings.
+// Without a baseline the buyer has no history to compare with, the transaction is not held against them.
+func aboveBaseline(input scoring.TransactionRiskScoreInput) bool {
+	if input.Baseline.Empty() {
+		return false
+	}
+	amount, err := strconv.ParseFloat(input.Transaction.Payment.Amount, 64)
+	if err != nil {
+		return false
+	}
+	baseline, err := strconv.ParseFloat(input.Baseline.Amount, 64)
+	if err != nil {
+		return false
+	}
+	return amount >= baseline
+}
@@ -6,7 +6,7 @@ import (
 )
 
 type TransactionRiskScoreInput struct {
-	Average     *history.AveragePayment
+	Baseline    *history.AverageBaseline
 	Last        *history.LastOrder
 	Transaction *domain.TransactionAnalysis
 }
@@ -1,6 +1,11 @@
 package domain
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
 
 type TransactionAnalysis struct {
 	Participants Participants `json:"participants"`
@@ -9,16 +14,36 @@ type TransactionAnalysis struct {
 }
 
 type Checkout struct {
-	Id          string    `json:"id"`
-	PaymentType CardInfo  `json:"paymentType"`
-	At          time.Time `json:"at"`
+	Id             string    `json:"id"`
+	PaymentType    CardInfo  `json:"paymentType"`
+	At             time.Time `json:"at"`
+	AtSource       string    `json:"atSource,omitempty"`
+	IdempotencyKey string    `json:"idempotencyKey,omitempty"`
 }
 
 type Payment struct {
-	Id       string `json:"id"`
-	Amount   string `json:"amount"`
-	Currency string `json:"currency"`
-	Status   string `json:"status"`
+	Id             string `json:"id"`
+	Amount         string `json:"amount"`
+	Currency       string `json:"currency"`
+	Status         string `json:"status"`
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
+}
+
+// IdempotencyKey identifies the transaction across deliveries, from the idempotency keys of its checkout and payment.
+// It is empty when neither has one, the transaction can't be told apart from another then.
+func (ta *TransactionAnalysis) IdempotencyKey() string {
+	if ta.Order.IdempotencyKey == "" && ta.Payment.IdempotencyKey == "" {
+		return ""
+	}
+	return ta.Order.IdempotencyKey + "/" + ta.Payment.IdempotencyKey
+}
+
+// Fingerprint is a hash of the whole transaction, telling a transaction delivered again from another one reusing its
+// idempotency key.
+func (ta *TransactionAnalysis) Fingerprint() string {
+	data, _ := json.Marshal(ta)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
 }
 
 type Participants struct {
@@ -144,6 +144,17 @@ func TestTransactionAnalysis_IsValid(t *testing.T) {
 	}
 }
 
+func TestTransactionAnalysis_IdempotencyKey(t *testing.T) {
+	analysis := &TransactionAnalysis{}
+	if key := analysis.IdempotencyKey(); key != "" {
+		t.Errorf("Expected no key without idempotency keys, got %q", key)
+	}
+	analysis.Payment.IdempotencyKey = "payment-key"
+	if key := analysis.IdempotencyKey(); key != "/payment-key" {
+		t.Errorf("Expected the payment key alone, got %q", key)
+	}
+}
+
 func TestPayment_IsCompleted(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -33,6 +33,11 @@ type ComponentValidationResult struct {
 
 // Component validates a transaction analysis and returns validation results
 func (tc *TransactionComponent) Component(transaction *TransactionAnalysis) (*ComponentValidationResult, error) {
+	return tc.ComponentAt(transaction, time.Now())
+}
+
+// ComponentAt validates a transaction analysis as of now, the age of the transaction is measured against it
+func (tc *TransactionComponent) ComponentAt(transaction *TransactionAnalysis, now time.Time) (*ComponentValidationResult, error) {
 	if transaction == nil {
 		return nil, errors.New("transaction cannot be nil")
 	}
@@ -67,7 +72,7 @@ func (tc *TransactionComponent) Component(transaction *TransactionAnalysis) (*Co
 	}
 
 	// Validate transaction age
-	if err := tc.validateTransactionAge(&transaction.Order); err != nil {
+	if err := tc.validateTransactionAge(&transaction.Order, now); err != nil {
 		result.IsValid = false
 		result.Errors = append(result.Errors, err.Error())
 	}
@@ -178,17 +183,17 @@ func (tc *TransactionComponent) validateCheckout(checkout *Checkout) error {
 }
 
 // validateTransactionAge validates if transaction is within acceptable age limit
-func (tc *TransactionComponent) validateTransactionAge(checkout *Checkout) error {
+func (tc *TransactionComponent) validateTransactionAge(checkout *Checkout, now time.Time) error {
 	if checkout.At.IsZero() {
 		return errors.New("checkout timestamp is required")
 	}
 
-	age := time.Since(checkout.At)
+	age := now.Sub(checkout.At)
 	if age > tc.maxTransactionAge {
 		return errors.New("transaction too old")
 	}
 
-	if checkout.At.After(time.Now().Add(time.Hour)) {
+	if checkout.At.After(now.Add(time.Hour)) {
 		return errors.New("transaction timestamp is in the future")
 	}
 
@@ -0,0 +1,7 @@
+package domain
+
+// TransactionRejection is a transaction left unscored because it breaks the rules of the transaction component.
+type TransactionRejection struct {
+	Transaction TransactionAnalysis `json:"transaction"`
+	Errors      []string            `json:"errors"`
+}
@@ -1,8 +1,19 @@
 package domain
 
+import "fraud-scoring/internal/domain/history"
+
 type ScoringResult struct {
 	Score       ScoreCard           `json:"score"`
 	Transaction TransactionAnalysis `json:"transaction"`
+	Provenance  ScoreProvenance     `json:"provenance"`
+	Decision    Decision            `json:"decision"`
+}
+
+// ScoreProvenance records where each history input of the score came from, so scores built on stale or default
+// data can be told apart.
+type ScoreProvenance struct {
+	LastOrder       history.Provenance `json:"lastOrder"`
+	AverageBaseline history.Provenance `json:"averageBaseline"`
 }
 
 type ScoreCard struct {
@@ -12,6 +23,11 @@ type ScoreCard struct {
 	CurrencyScore     CurrencyScoreCard     `json:"currencyScore"`
 }
 
+// Total adds up the points of every criterion.
+func (sc ScoreCard) Total() int {
+	return sc.ValueScore.Score + sc.SellerScore.Score + sc.AverageValueScore.Score + sc.CurrencyScore.Score
+}
+
 type Transaction struct {
 	Id string `json:"id"`
 }
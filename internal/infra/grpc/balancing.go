@@ -0,0 +1,132 @@
+package api
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/balancer/leastrequest"
+	"google.golang.org/grpc/balancer/roundrobin"
+	_ "google.golang.org/grpc/health"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	DiscoveryStatic = "static"
+	DiscoveryDNS    = "dns"
+
+	BalancerRoundRobin   = "round_robin"
+	BalancerLeastRequest = "least_request"
+
+	staticScheme = "user-transactions"
+)
+
+var backendRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "fraud_scoring_grpc_backend_requests_total",
+	Help: "User transactions calls per configured endpoint, method and status code.",
+}, []string{"backend", "method", "code"})
+
+var backendLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "fraud_scoring_grpc_backend_request_duration_seconds",
+	Help:    "Latency of user transactions calls per configured endpoint and method.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"backend", "method"})
+
+type LoadBalancingConfig struct {
+	// Endpoints lists the replicas to balance across. When empty, Host is used.
+	Endpoints []string
+	// Discovery is "static" to dial Host or Endpoints as they are, or "dns" to resolve every address behind Host.
+	Discovery string
+	// Policy is "round_robin" or "least_request".
+	Policy string
+	// HealthCheck ejects backends that fail the standard gRPC health check until they report serving again.
+	HealthCheck bool
+	// HealthCheckService is the service name asked to the health check, empty for the whole server.
+	HealthCheckService string
+}
+
+func (c *UserTransactionsConfig) loadBalancingPolicy() map[string]any {
+	if c.LoadBalancing.Policy == BalancerLeastRequest {
+		return map[string]any{leastrequest.Name: map[string]any{"choiceCount": 2}}
+	}
+	return map[string]any{roundrobin.Name: map[string]any{}}
+}
+
+// target returns the address to dial and, for a static list of endpoints, the resolver that serves them and the
+// dialer naming their connections. Every endpoint is checked against its own host name over TLS, the target only names
+// the list in the logs.
+func (c *UserTransactionsConfig) target() (string, []grpc.DialOption) {
+	if len(c.LoadBalancing.Endpoints) > 0 {
+		r := manual.NewBuilderWithScheme(staticScheme)
+		addrs := make([]resolver.Address, 0, len(c.LoadBalancing.Endpoints))
+		for _, endpoint := range c.LoadBalancing.Endpoints {
+			addrs = append(addrs, resolver.Address{Addr: endpoint, ServerName: endpointHost(endpoint)})
+		}
+		r.InitialState(resolver.State{Addresses: addrs})
+		return staticScheme + ":///" + strings.Join(c.LoadBalancing.Endpoints, ","),
+			[]grpc.DialOption{grpc.WithResolvers(r), grpc.WithContextDialer(dialEndpoint)}
+	}
+	if c.LoadBalancing.Discovery == DiscoveryDNS {
+		return "dns:///" + c.Host, nil
+	}
+	return c.Host, nil
+}
+
+// endpointHost strips the port of endpoint.
+func endpointHost(endpoint string) string {
+	if h, _, err := net.SplitHostPort(endpoint); err == nil {
+		return h
+	}
+	return endpoint
+}
+
+// endpointConn is a connection to a configured endpoint, its remote address tells the endpoint it was dialed for
+// whatever the endpoint resolved to.
+type endpointConn struct {
+	net.Conn
+	endpoint string
+}
+
+func (ec *endpointConn) RemoteAddr() net.Addr {
+	return endpointAddr{Addr: ec.Conn.RemoteAddr(), endpoint: ec.endpoint}
+}
+
+type endpointAddr struct {
+	net.Addr
+	endpoint string
+}
+
+func dialEndpoint(ctx context.Context, endpoint string) (net.Conn, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &endpointConn{Conn: conn, endpoint: endpoint}, nil
+}
+
+// backendMetricsInterceptor records every call against the configured endpoint that served it, or against host when
+// the backends are not configured one by one, so the metrics don't grow with the addresses the backends move to.
+func backendMetricsInterceptor(host string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		var p peer.Peer
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Peer(&p))...)
+		backend := "none"
+		if addr, ok := p.Addr.(endpointAddr); ok {
+			backend = addr.endpoint
+		} else if p.Addr != nil {
+			backend = host
+		}
+		backendRequests.WithLabelValues(backend, method, status.Code(err).String()).Inc()
+		backendLatency.WithLabelValues(backend, method).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type namedBackend struct {
+	UnimplementedUserTransactionsServiceServer
+	name string
+}
+
+func (nb *namedBackend) GetLastUserTransaction(context.Context, *LastUserTransactionRequest) (*LastUserTransactionResponse, error) {
+	return &LastUserTransactionResponse{SellerId: nb.name}, nil
+}
+
+func startBackend(t *testing.T, name string, opts ...grpc.ServerOption) (string, *health.Server) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	srv := grpc.NewServer(opts...)
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(srv, hs)
+	RegisterUserTransactionsServiceServer(srv, &namedBackend{name: name})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String(), hs
+}
+
+func dialBackends(t *testing.T, policy string, endpoints ...string) UserTransactionsServiceClient {
+	config := NewUserTransactionsConfig()
+	config.ConnectTimeout = 5 * time.Second
+	config.LoadBalancing = LoadBalancingConfig{Endpoints: endpoints, Policy: policy, HealthCheck: true}
+	log := zaptest.NewLogger(t)
+	conn, err := NewUserTransactionsConn(config, NewCircuitBreakers(config, log), log)
+	if err != nil {
+		t.Fatalf("Expected connection to succeed, got %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewUserTransactionGrpc(conn)
+}
+
+// servedBy calls the client n times and counts the answers per backend.
+func servedBy(t *testing.T, client UserTransactionsServiceClient, n int) map[string]int {
+	served := map[string]int{}
+	for i := 0; i < n; i++ {
+		res, err := client.GetLastUserTransaction(context.Background(), &LastUserTransactionRequest{Document: "12345678901"})
+		if err != nil {
+			t.Fatalf("Expected call to succeed, got %v", err)
+		}
+		served[res.SellerId]++
+	}
+	return served
+}
+
+func TestNewUserTransactionsConn_BalancesAcrossEndpoints(t *testing.T) {
+	for _, policy := range []string{BalancerRoundRobin, BalancerLeastRequest} {
+		t.Run(policy, func(t *testing.T) {
+			a, _ := startBackend(t, "a")
+			b, _ := startBackend(t, "b")
+			client := dialBackends(t, policy, a, b)
+
+			// Subchannels become ready independently, give the second one time to join.
+			deadline := time.Now().Add(5 * time.Second)
+			for {
+				served := servedBy(t, client, 20)
+				if served["a"] > 0 && served["b"] > 0 {
+					return
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("Expected calls to reach both backends, got %v", served)
+				}
+				time.Sleep(50 * time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestNewUserTransactionsConn_TLSStaticEndpoints(t *testing.T) {
+	ca := newTestCA(t)
+	serverCert, serverKey := ca.issue(t, "user-transactions", x509.ExtKeyUsageServerAuth)
+	pair, err := tls.X509KeyPair(serverCert, serverKey)
+	if err != nil {
+		t.Fatalf("Failed to load server certificate: %v", err)
+	}
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	writeFile(t, caFile, ca.pem, time.Now())
+	creds := grpc.Creds(credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{pair}}))
+	a, _ := startBackend(t, "a", creds)
+	b, _ := startBackend(t, "b", creds)
+
+	config := NewUserTransactionsConfig()
+	config.ConnectTimeout = 5 * time.Second
+	config.TLS = TLSConfig{Enabled: true, CAFile: caFile}
+	config.LoadBalancing = LoadBalancingConfig{Endpoints: []string{a, b}, Policy: BalancerRoundRobin}
+	log := zaptest.NewLogger(t)
+	conn, err := NewUserTransactionsConn(config, NewCircuitBreakers(config, log), log)
+	if err != nil {
+		t.Fatalf("Expected TLS connection to the endpoints to succeed, got %v", err)
+	}
+	defer conn.Close()
+	client := NewUserTransactionGrpc(conn)
+
+	method := "/user.UserTransactionsService/GetLastUserTransaction"
+	before := testutil.ToFloat64(backendRequests.WithLabelValues(a, method, "OK")) +
+		testutil.ToFloat64(backendRequests.WithLabelValues(b, method, "OK"))
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		served := servedBy(t, client, 20)
+		if served["a"] > 0 && served["b"] > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected calls to reach both endpoints over TLS, got %v", served)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	after := testutil.ToFloat64(backendRequests.WithLabelValues(a, method, "OK")) +
+		testutil.ToFloat64(backendRequests.WithLabelValues(b, method, "OK"))
+	if after-before < 20 {
+		t.Errorf("Expected calls to be recorded per configured endpoint, got %v more", after-before)
+	}
+}
+
+func TestNewUserTransactionsConn_EjectsUnhealthyBackends(t *testing.T) {
+	a, _ := startBackend(t, "a")
+	b, hb := startBackend(t, "b")
+	hb.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	client := dialBackends(t, BalancerRoundRobin, a, b)
+
+	served := servedBy(t, client, 20)
+	if served["b"] != 0 || served["a"] != 20 {
+		t.Errorf("Expected unhealthy backend to receive no calls, got %v", served)
+	}
+}
+
+func TestUserTransactionsConfig_ServiceConfig(t *testing.T) {
+	config := NewUserTransactionsConfig()
+	config.LoadBalancing = LoadBalancingConfig{Policy: BalancerLeastRequest, HealthCheck: true, HealthCheckService: "user.UserTransactionsService"}
+
+	var sc struct {
+		LoadBalancingConfig []map[string]json.RawMessage `json:"loadBalancingConfig"`
+		HealthCheckConfig   struct {
+			ServiceName string `json:"serviceName"`
+		} `json:"healthCheckConfig"`
+	}
+	if err := json.Unmarshal([]byte(config.serviceConfig()), &sc); err != nil {
+		t.Fatalf("Expected valid service config JSON, got %v", err)
+	}
+	if _, ok := sc.LoadBalancingConfig[0]["least_request_experimental"]; !ok {
+		t.Errorf("Expected least request policy, got %v", sc.LoadBalancingConfig)
+	}
+	if sc.HealthCheckConfig.ServiceName != "user.UserTransactionsService" {
+		t.Errorf("Expected health check service name, got %q", sc.HealthCheckConfig.ServiceName)
+	}
+}
+
+func TestUserTransactionsConfig_Target(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         *UserTransactionsConfig
+		expectedTarget string
+		expectResolver bool
+	}{
+		{
+			name:           "Single host",
+			config:         &UserTransactionsConfig{Host: "localhost:50051"},
+			expectedTarget: "localhost:50051",
+		},
+		{
+			name:           "DNS discovery",
+			config:         &UserTransactionsConfig{Host: "user-transactions:50051", LoadBalancing: LoadBalancingConfig{Discovery: DiscoveryDNS}},
+			expectedTarget: "dns:///user-transactions:50051",
+		},
+		{
+			name:           "Static endpoints",
+			config:         &UserTransactionsConfig{LoadBalancing: LoadBalancingConfig{Endpoints: []string{"10.0.0.1:50051", "10.0.0.2:50051"}}},
+			expectedTarget: "user-transactions:///10.0.0.1:50051,10.0.0.2:50051",
+			expectResolver: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, opts := tt.config.target()
+			if target != tt.expectedTarget {
+				t.Errorf("Expected target %s, got %s", tt.expectedTarget, target)
+			}
+			if (len(opts) > 0) != tt.expectResolver {
+				t.Errorf("Expected resolver option %v, got %d options", tt.expectResolver, len(opts))
+			}
+		})
+	}
+}
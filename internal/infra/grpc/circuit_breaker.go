@@ -0,0 +1,231 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrCircuitOpen is returned without reaching the server while the breaker of the called method is open.
+var ErrCircuitOpen = status.Error(codes.Unavailable, "circuit breaker is open")
+
+var breakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "fraud_scoring_grpc_circuit_breaker_state",
+	Help: "Circuit breaker state per gRPC method (0 closed, 1 open, 2 half-open).",
+}, []string{"method"})
+
+var breakerTransitions = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "fraud_scoring_grpc_circuit_breaker_transitions_total",
+	Help: "Circuit breaker state changes per gRPC method.",
+}, []string{"method", "to"})
+
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+func (bs BreakerState) String() string {
+	switch bs {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the breaker.
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before letting a probe call through.
+	OpenTimeout time.Duration
+}
+
+type circuitBreaker struct {
+	mu       sync.Mutex
+	method   string
+	cfg      BreakerConfig
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+	now      func() time.Time
+	onChange func(method string, from, to BreakerState)
+}
+
+// allow reports whether a call may proceed. While half-open only a single probe call is let through.
+func (cb *circuitBreaker) allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	switch cb.state {
+	case BreakerOpen:
+		if cb.now().Sub(cb.openedAt) < cb.cfg.OpenTimeout {
+			return false
+		}
+		cb.transition(BreakerHalfOpen)
+		cb.probing = true
+		return true
+	case BreakerHalfOpen:
+		if cb.probing {
+			return false
+		}
+		cb.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (cb *circuitBreaker) success() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.failures = 0
+	cb.probing = false
+	if cb.state != BreakerClosed {
+		cb.transition(BreakerClosed)
+	}
+}
+
+func (cb *circuitBreaker) failure() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.probing = false
+	cb.failures++
+	if cb.state == BreakerHalfOpen || (cb.state == BreakerClosed && cb.failures >= cb.cfg.FailureThreshold) {
+		cb.openedAt = cb.now()
+		cb.transition(BreakerOpen)
+	}
+}
+
+// release gives back a probe slot for a call whose outcome says nothing about the server, e.g. a cancelled caller.
+func (cb *circuitBreaker) release() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.probing = false
+}
+
+// rejecting reports whether the breaker turns calls away: it is open and not due for a probe yet.
+func (cb *circuitBreaker) rejecting() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state == BreakerOpen && cb.now().Sub(cb.openedAt) < cb.cfg.OpenTimeout
+}
+
+func (cb *circuitBreaker) State() BreakerState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state
+}
+
+func (cb *circuitBreaker) transition(to BreakerState) {
+	from := cb.state
+	cb.state = to
+	if cb.onChange != nil {
+		cb.onChange(cb.method, from, to)
+	}
+}
+
+// CircuitBreakers keeps one breaker per gRPC method, so a failing RPC does not cut off the healthy ones.
+type CircuitBreakers struct {
+	mu       sync.Mutex
+	cfg      BreakerConfig
+	breakers map[string]*circuitBreaker
+	log      *zap.Logger
+}
+
+func (cbs *CircuitBreakers) breaker(method string) *circuitBreaker {
+	cbs.mu.Lock()
+	defer cbs.mu.Unlock()
+	cb, ok := cbs.breakers[method]
+	if !ok {
+		cb = &circuitBreaker{method: method, cfg: cbs.cfg, now: time.Now, onChange: cbs.stateChanged}
+		cbs.breakers[method] = cb
+		breakerState.WithLabelValues(method).Set(float64(BreakerClosed))
+	}
+	return cb
+}
+
+func (cbs *CircuitBreakers) stateChanged(method string, from, to BreakerState) {
+	cbs.log.Warn("circuit breaker state changed",
+		zap.String("method", method),
+		zap.Stringer("from", from),
+		zap.Stringer("to", to),
+	)
+	breakerState.WithLabelValues(method).Set(float64(to))
+	breakerTransitions.WithLabelValues(method, to.String()).Inc()
+}
+
+// States returns the current breaker state of every method called so far.
+func (cbs *CircuitBreakers) States() map[string]BreakerState {
+	cbs.mu.Lock()
+	defer cbs.mu.Unlock()
+	states := make(map[string]BreakerState, len(cbs.breakers))
+	for method, cb := range cbs.breakers {
+		states[method] = cb.State()
+	}
+	return states
+}
+
+// Check fails while the breaker of a method turns calls away. Once a breaker is due for a probe it passes again, so
+// the callers waiting on it resume and their next call probes the server.
+func (cbs *CircuitBreakers) Check(_ context.Context) error {
+	cbs.mu.Lock()
+	defer cbs.mu.Unlock()
+	for method, cb := range cbs.breakers {
+		if cb.rejecting() {
+			return fmt.Errorf("circuit breaker of %s is open", method)
+		}
+	}
+	return nil
+}
+
+func (cbs *CircuitBreakers) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		cb := cbs.breaker(method)
+		if !cb.allow() {
+			return ErrCircuitOpen
+		}
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		switch {
+		case err == nil:
+			cb.success()
+		case isServerFailure(err):
+			cb.failure()
+		case status.Code(err) == codes.Canceled:
+			cb.release()
+		default:
+			// The server answered, even if with an application error such as NotFound.
+			cb.success()
+		}
+		return err
+	}
+}
+
+func isServerFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal, codes.Unknown:
+		return true
+	default:
+		return false
+	}
+}
+
+func NewCircuitBreakers(config *UserTransactionsConfig, log *zap.Logger) *CircuitBreakers {
+	return &CircuitBreakers{
+		cfg:      config.Breaker,
+		breakers: map[string]*circuitBreaker{},
+		log:      log,
+	}
+}
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestBreaker(threshold int, openTimeout time.Duration, now *time.Time) *circuitBreaker {
+	return &circuitBreaker{
+		method: "/user.UserTransactionsService/GetLastUserTransaction",
+		cfg:    BreakerConfig{FailureThreshold: threshold, OpenTimeout: openTimeout},
+		now:    func() time.Time { return *now },
+	}
+}
+
+func TestCircuitBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	now := time.Now()
+	cb := newTestBreaker(3, time.Second, &now)
+
+	for i := 0; i < 2; i++ {
+		if !cb.allow() {
+			t.Fatalf("Expected call %d to be allowed", i)
+		}
+		cb.failure()
+	}
+	cb.success()
+	for i := 0; i < 2; i++ {
+		cb.allow()
+		cb.failure()
+	}
+	if cb.State() != BreakerClosed {
+		t.Fatalf("Expected success to reset the failure count, got %s", cb.State())
+	}
+
+	cb.allow()
+	cb.failure()
+	if cb.State() != BreakerOpen {
+		t.Fatalf("Expected breaker to be open, got %s", cb.State())
+	}
+	if cb.allow() {
+		t.Error("Expected open breaker to reject calls")
+	}
+}
+
+func TestCircuitBreaker_HalfOpenProbe(t *testing.T) {
+	now := time.Now()
+	cb := newTestBreaker(1, time.Second, &now)
+	var transitions []BreakerState
+	cb.onChange = func(method string, from, to BreakerState) {
+		transitions = append(transitions, to)
+	}
+
+	cb.allow()
+	cb.failure()
+
+	now = now.Add(time.Second)
+	if !cb.allow() {
+		t.Fatal("Expected a probe call after the open timeout")
+	}
+	if cb.allow() {
+		t.Error("Expected a single probe call while half-open")
+	}
+	cb.failure()
+	if cb.State() != BreakerOpen {
+		t.Fatalf("Expected failed probe to reopen the breaker, got %s", cb.State())
+	}
+
+	now = now.Add(time.Second)
+	cb.allow()
+	cb.success()
+	if cb.State() != BreakerClosed {
+		t.Fatalf("Expected successful probe to close the breaker, got %s", cb.State())
+	}
+
+	expected := []BreakerState{BreakerOpen, BreakerHalfOpen, BreakerOpen, BreakerHalfOpen, BreakerClosed}
+	if len(transitions) != len(expected) {
+		t.Fatalf("Expected transitions %v, got %v", expected, transitions)
+	}
+	for i := range expected {
+		if transitions[i] != expected[i] {
+			t.Errorf("Expected transition %d to be %s, got %s", i, expected[i], transitions[i])
+		}
+	}
+}
+
+func TestCircuitBreakers_UnaryClientInterceptor(t *testing.T) {
+	config := &UserTransactionsConfig{Breaker: BreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute}}
+	cbs := NewCircuitBreakers(config, zaptest.NewLogger(t))
+	interceptor := cbs.UnaryClientInterceptor()
+
+	calls := 0
+	failing := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		calls++
+		return status.Error(codes.Unavailable, "down")
+	}
+	notFound := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		calls++
+		return status.Error(codes.NotFound, "no transactions")
+	}
+
+	for i := 0; i < 3; i++ {
+		_ = interceptor(context.Background(), "/svc/NotFound", nil, nil, nil, notFound)
+	}
+	for i := 0; i < 2; i++ {
+		_ = interceptor(context.Background(), "/svc/Failing", nil, nil, nil, failing)
+	}
+
+	err := interceptor(context.Background(), "/svc/Failing", nil, nil, nil, failing)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if calls != 5 {
+		t.Errorf("Expected open breaker to short-circuit the call, got %d calls", calls)
+	}
+
+	states := cbs.States()
+	if states["/svc/Failing"] != BreakerOpen {
+		t.Errorf("Expected failing method breaker to be open, got %s", states["/svc/Failing"])
+	}
+	if states["/svc/NotFound"] != BreakerClosed {
+		t.Errorf("Expected application errors to keep the breaker closed, got %s", states["/svc/NotFound"])
+	}
+}
+
+func TestCircuitBreakers_Check(t *testing.T) {
+	config := &UserTransactionsConfig{Breaker: BreakerConfig{FailureThreshold: 1, OpenTimeout: time.Minute}}
+	cbs := NewCircuitBreakers(config, zaptest.NewLogger(t))
+	if err := cbs.Check(context.Background()); err != nil {
+		t.Fatalf("Expected no breaker to turn calls away, got %v", err)
+	}
+
+	now := time.Now()
+	cb := cbs.breaker("/svc/Failing")
+	cb.now = func() time.Time { return now }
+	cb.failure()
+	if err := cbs.Check(context.Background()); err == nil {
+		t.Fatal("Expected the check to fail while the breaker is open")
+	}
+
+	// Due for a probe, the callers may try again
+	now = now.Add(time.Minute)
+	if err := cbs.Check(context.Background()); err != nil {
+		t.Errorf("Expected the check to pass once a probe is due, got %v", err)
+	}
+}
+
+func TestNewUserTransactionsConn_ConnectivityCheck(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	srv := grpc.NewServer()
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	config := NewUserTransactionsConfig()
+	config.Host = lis.Addr().String()
+	config.ConnectTimeout = 5 * time.Second
+	log := zaptest.NewLogger(t)
+
+	conn, err := NewUserTransactionsConn(config, NewCircuitBreakers(config, log), log)
+	if err != nil {
+		t.Fatalf("Expected connection to succeed, got %v", err)
+	}
+	conn.Close()
+}
+
+func TestNewUserTransactionsConn_FailsFast(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+
+	config := NewUserTransactionsConfig()
+	config.Host = addr
+	config.ConnectTimeout = 200 * time.Millisecond
+	log := zaptest.NewLogger(t)
+
+	start := time.Now()
+	_, err = NewUserTransactionsConn(config, NewCircuitBreakers(config, log), log)
+	if err == nil {
+		t.Fatal("Expected connection to an unreachable host to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected connectivity check to honour the timeout, took %s", elapsed)
+	}
+}
@@ -0,0 +1,206 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type TLSConfig struct {
+	Enabled bool
+	// CAFile verifies the server certificate, the system roots are used when empty.
+	CAFile string
+	// CertFile and KeyFile enable mutual TLS. They are read again whenever they change on disk.
+	CertFile   string
+	KeyFile    string
+	ServerName string
+}
+
+type TokenConfig struct {
+	// Token is sent as a bearer token on every call.
+	Token string
+	// File holds the bearer token instead of Token and is read again whenever it changes on disk.
+	File string
+	// AllowInsecure lets the bearer token be sent in cleartext when TLS is disabled, for local setups only.
+	AllowInsecure bool
+}
+
+func (c *UserTransactionsConfig) transportCredentials(log *zap.Logger) (credentials.TransportCredentials, error) {
+	if !c.TLS.Enabled {
+		return insecure.NewCredentials(), nil
+	}
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: c.TLS.ServerName,
+	}
+	if c.TLS.CAFile != "" {
+		pem, err := os.ReadFile(c.TLS.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no certificates found in CA file " + c.TLS.CAFile)
+		}
+	}
+	if c.TLS.CertFile != "" || c.TLS.KeyFile != "" {
+		reloader, err := newCertificateReloader(c.TLS.CertFile, c.TLS.KeyFile, log)
+		if err != nil {
+			return nil, err
+		}
+		cfg.GetClientCertificate = reloader.GetClientCertificate
+	}
+	return credentials.NewTLS(cfg), nil
+}
+
+// perRPCCredentials returns nil when no bearer token is configured. It refuses a token without TLS unless the token
+// is explicitly allowed to travel in cleartext.
+func (c *UserTransactionsConfig) perRPCCredentials() (credentials.PerRPCCredentials, error) {
+	if (c.Token.File != "" || c.Token.Token != "") && !c.TLS.Enabled && !c.Token.AllowInsecure {
+		return nil, errors.New("a user transactions token is configured without TLS, enable TLS or allow the token to be sent in cleartext")
+	}
+	switch {
+	case c.Token.File != "":
+		token := &bearerToken{file: &watchedFile{path: c.Token.File}, requireTLS: c.TLS.Enabled}
+		if _, err := token.current(); err != nil {
+			return nil, err
+		}
+		return token, nil
+	case c.Token.Token != "":
+		return &bearerToken{token: c.Token.Token, requireTLS: c.TLS.Enabled}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// watchedFile tells whether a file changed since it was last read, so rotated secrets are picked up without a restart.
+type watchedFile struct {
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+func (wf *watchedFile) changed() (bool, error) {
+	info, err := os.Stat(wf.path)
+	if err != nil {
+		return false, err
+	}
+	return !info.ModTime().Equal(wf.modTime) || info.Size() != wf.size, nil
+}
+
+func (wf *watchedFile) read() ([]byte, error) {
+	info, err := os.Stat(wf.path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(wf.path)
+	if err != nil {
+		return nil, err
+	}
+	wf.modTime, wf.size = info.ModTime(), info.Size()
+	return data, nil
+}
+
+type certificateReloader struct {
+	mu   sync.Mutex
+	cert *watchedFile
+	key  *watchedFile
+	pair *tls.Certificate
+	log  *zap.Logger
+}
+
+func newCertificateReloader(certFile, keyFile string, log *zap.Logger) (*certificateReloader, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("both client certificate and key files are required for mutual TLS")
+	}
+	r := &certificateReloader{cert: &watchedFile{path: certFile}, key: &watchedFile{path: keyFile}, log: log}
+	if err := r.load(); err != nil {
+		return nil, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+	return r, nil
+}
+
+func (r *certificateReloader) load() error {
+	certPEM, err := r.cert.read()
+	if err != nil {
+		return err
+	}
+	keyPEM, err := r.key.read()
+	if err != nil {
+		return err
+	}
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return err
+	}
+	r.pair = &pair
+	return nil
+}
+
+// GetClientCertificate serves the last good certificate. A rotation caught half written is retried on the next handshake.
+func (r *certificateReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	certChanged, certErr := r.cert.changed()
+	keyChanged, keyErr := r.key.changed()
+	if certErr == nil && keyErr == nil && (certChanged || keyChanged) {
+		if err := r.load(); err != nil {
+			r.log.Warn("failed to reload client certificate", zap.String("error", err.Error()))
+		} else {
+			r.log.Info("client certificate reloaded", zap.String("cert_file", r.cert.path))
+		}
+	}
+	return r.pair, nil
+}
+
+type bearerToken struct {
+	mu         sync.Mutex
+	token      string
+	file       *watchedFile
+	requireTLS bool
+}
+
+func (bt *bearerToken) current() (string, error) {
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+	if bt.file == nil {
+		return bt.token, nil
+	}
+	changed, err := bt.file.changed()
+	if err != nil && bt.token == "" {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	if changed {
+		data, err := bt.file.read()
+		if err != nil {
+			return "", fmt.Errorf("failed to read token file: %w", err)
+		}
+		bt.token = strings.TrimSpace(string(data))
+	}
+	if bt.token == "" {
+		return "", errors.New("token file " + bt.file.path + " is empty")
+	}
+	return bt.token, nil
+}
+
+func (bt *bearerToken) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	token, err := bt.current()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"authorization": "Bearer " + token}, nil
+}
+
+func (bt *bearerToken) RequireTransportSecurity() bool {
+	return bt.requireTLS
+}
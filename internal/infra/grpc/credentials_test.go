@@ -0,0 +1,235 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate CA key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create CA certificate: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM encoded certificate and key signed by the CA.
+func (ca *testCA) issue(t *testing.T, cn string, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, path string, data []byte, modTime time.Time) {
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("Failed to touch %s: %v", path, err)
+	}
+}
+
+func TestCertificateReloader_ReloadsRotatedCertificate(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	certFile, keyFile := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+	certPEM, keyPEM := ca.issue(t, "client-v1", x509.ExtKeyUsageClientAuth)
+	writeFile(t, certFile, certPEM, time.Now().Add(-time.Minute))
+	writeFile(t, keyFile, keyPEM, time.Now().Add(-time.Minute))
+
+	reloader, err := newCertificateReloader(certFile, keyFile, zaptest.NewLogger(t))
+	if err != nil {
+		t.Fatalf("Expected certificate to load, got %v", err)
+	}
+	first, _ := reloader.GetClientCertificate(nil)
+
+	certPEM, keyPEM = ca.issue(t, "client-v2", x509.ExtKeyUsageClientAuth)
+	writeFile(t, certFile, certPEM, time.Now())
+	writeFile(t, keyFile, keyPEM, time.Now())
+
+	second, _ := reloader.GetClientCertificate(nil)
+	leaf, err := x509.ParseCertificate(second.Certificate[0])
+	if err != nil {
+		t.Fatalf("Failed to parse reloaded certificate: %v", err)
+	}
+	if first == second || leaf.Subject.CommonName != "client-v2" {
+		t.Errorf("Expected rotated certificate to be served, got %s", leaf.Subject.CommonName)
+	}
+
+	writeFile(t, keyFile, []byte("half written"), time.Now().Add(time.Second))
+	third, err := reloader.GetClientCertificate(nil)
+	if err != nil || third != second {
+		t.Error("Expected the last good certificate while the rotation is incomplete")
+	}
+}
+
+func TestNewCertificateReloader_RequiresCertAndKey(t *testing.T) {
+	if _, err := newCertificateReloader("client.crt", "", zaptest.NewLogger(t)); err == nil {
+		t.Error("Expected error when the key file is missing")
+	}
+}
+
+func TestBearerToken_ReadsRotatedTokenFile(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	writeFile(t, tokenFile, []byte("first\n"), time.Now().Add(-time.Minute))
+
+	config := &UserTransactionsConfig{Token: TokenConfig{File: tokenFile, AllowInsecure: true}}
+	creds, err := config.perRPCCredentials()
+	if err != nil {
+		t.Fatalf("Expected token credentials, got %v", err)
+	}
+
+	md, _ := creds.GetRequestMetadata(context.Background())
+	if md["authorization"] != "Bearer first" {
+		t.Errorf("Expected first token, got %q", md["authorization"])
+	}
+
+	writeFile(t, tokenFile, []byte("second"), time.Now())
+	md, _ = creds.GetRequestMetadata(context.Background())
+	if md["authorization"] != "Bearer second" {
+		t.Errorf("Expected rotated token, got %q", md["authorization"])
+	}
+	if creds.RequireTransportSecurity() {
+		t.Error("Expected token to be allowed without TLS when TLS is disabled")
+	}
+}
+
+func TestPerRPCCredentials_RefusesTokenWithoutTLS(t *testing.T) {
+	config := &UserTransactionsConfig{Token: TokenConfig{Token: "secret"}}
+	if _, err := config.perRPCCredentials(); err == nil {
+		t.Error("Expected a token without TLS to be refused")
+	}
+
+	config.TLS.Enabled = true
+	creds, err := config.perRPCCredentials()
+	if err != nil {
+		t.Fatalf("Expected token credentials over TLS, got %v", err)
+	}
+	if !creds.RequireTransportSecurity() {
+		t.Error("Expected token to require TLS")
+	}
+}
+
+func TestPerRPCCredentials_NoToken(t *testing.T) {
+	creds, err := (&UserTransactionsConfig{}).perRPCCredentials()
+	if err != nil || creds != nil {
+		t.Errorf("Expected no credentials, got %v, %v", creds, err)
+	}
+}
+
+func TestNewUserTransactionsConn_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	serverCert, serverKey := ca.issue(t, "user-transactions", x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := ca.issue(t, "fraud-scoring", x509.ExtKeyUsageClientAuth)
+	caFile := filepath.Join(dir, "ca.crt")
+	writeFile(t, caFile, ca.pem, time.Now())
+	writeFile(t, filepath.Join(dir, "client.crt"), clientCert, time.Now())
+	writeFile(t, filepath.Join(dir, "client.key"), clientKey, time.Now())
+
+	pair, err := tls.X509KeyPair(serverCert, serverKey)
+	if err != nil {
+		t.Fatalf("Failed to load server certificate: %v", err)
+	}
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	authorization := make(chan string, 1)
+	srv := grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(&tls.Config{
+			Certificates: []tls.Certificate{pair},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    clientCAs,
+		})),
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			md, _ := metadata.FromIncomingContext(ctx)
+			authorization <- md.Get("authorization")[0]
+			return handler(ctx, req)
+		}),
+	)
+	RegisterUserTransactionsServiceServer(srv, &UnimplementedUserTransactionsServiceServer{})
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	config := NewUserTransactionsConfig()
+	config.Host = lis.Addr().String()
+	config.ConnectTimeout = 5 * time.Second
+	config.TLS = TLSConfig{
+		Enabled:  true,
+		CAFile:   caFile,
+		CertFile: filepath.Join(dir, "client.crt"),
+		KeyFile:  filepath.Join(dir, "client.key"),
+	}
+	config.Token = TokenConfig{Token: "secret"}
+	log := zaptest.NewLogger(t)
+
+	conn, err := NewUserTransactionsConn(config, NewCircuitBreakers(config, log), log)
+	if err != nil {
+		t.Fatalf("Expected mutual TLS connection to succeed, got %v", err)
+	}
+	defer conn.Close()
+
+	_, _ = NewUserTransactionGrpc(conn).GetLastUserTransaction(context.Background(), &LastUserTransactionRequest{Document: "12345678901"})
+	if got := <-authorization; got != "Bearer secret" {
+		t.Errorf("Expected bearer token to be sent, got %q", got)
+	}
+}
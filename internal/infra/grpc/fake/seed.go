@@ -0,0 +1,73 @@
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Seed is the data served by the fake user transactions service, keyed by buyer document.
+type Seed struct {
+	Users map[string]*User `json:"users"`
+}
+
+type User struct {
+	LastTransaction *Transaction `json:"lastTransaction,omitempty"`
+	// MonthlyAverages maps a YYYY-MM month to the average value the buyer spent in it.
+	MonthlyAverages map[string]string `json:"monthlyAverages,omitempty"`
+	// Faults are applied in order to the calls made for the document.
+	Faults []*Fault `json:"faults,omitempty"`
+}
+
+type Transaction struct {
+	SellerId string `json:"sellerId"`
+	Currency string `json:"currency"`
+	Value    string `json:"value"`
+}
+
+// Fault delays and optionally fails calls for a document.
+type Fault struct {
+	// Method restricts the fault to GetUserMonthAverage or GetLastUserTransaction, empty matches both.
+	Method  string   `json:"method,omitempty"`
+	Latency Duration `json:"latency,omitempty"`
+	// Code is a gRPC status code name such as "UNAVAILABLE". Unset answers normally after the latency.
+	Code    codes.Code `json:"code,omitempty"`
+	Message string     `json:"message,omitempty"`
+	// Times is how many calls the fault applies to before the next one takes over, zero applies it to every call.
+	Times int `json:"times,omitempty"`
+}
+
+// Duration reads Go duration strings such as "250ms" from JSON.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func LoadSeed(path string) (*Seed, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	seed := &Seed{}
+	if err := json.Unmarshal(b, seed); err != nil {
+		return nil, fmt.Errorf("invalid seed file %s: %w", path, err)
+	}
+	return seed, nil
+}
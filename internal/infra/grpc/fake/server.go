@@ -0,0 +1,94 @@
+package fake
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	api "fraud-scoring/internal/infra/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server is an in-memory UserTransactionsService answering from a Seed.
+type Server struct {
+	api.UnimplementedUserTransactionsServiceServer
+	mu   sync.Mutex
+	seed *Seed
+}
+
+func (s *Server) GetUserMonthAverage(ctx context.Context, req *api.UserMonthAverageRequest) (*api.UserMonthAverageResponse, error) {
+	user, err := s.user(ctx, "GetUserMonthAverage", req.Document)
+	if err != nil {
+		return nil, err
+	}
+	total, ok := user.MonthlyAverages[req.Month]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no transactions for %s in %s", req.Document, req.Month)
+	}
+	return &api.UserMonthAverageResponse{Month: req.Month, Document: req.Document, Total: total}, nil
+}
+
+func (s *Server) GetLastUserTransaction(ctx context.Context, req *api.LastUserTransactionRequest) (*api.LastUserTransactionResponse, error) {
+	user, err := s.user(ctx, "GetLastUserTransaction", req.Document)
+	if err != nil {
+		return nil, err
+	}
+	if user.LastTransaction == nil {
+		return nil, status.Errorf(codes.NotFound, "no transactions for %s", req.Document)
+	}
+	return &api.LastUserTransactionResponse{
+		Document: req.Document,
+		SellerId: user.LastTransaction.SellerId,
+		Currency: user.LastTransaction.Currency,
+		Value:    user.LastTransaction.Value,
+	}, nil
+}
+
+// user looks the document up after applying its next fault for method.
+func (s *Server) user(ctx context.Context, method, document string) (*User, error) {
+	s.mu.Lock()
+	user, ok := s.seed.Users[document]
+	var fault Fault
+	if ok {
+		fault = s.nextFault(user, method)
+	}
+	s.mu.Unlock()
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown document %s", document)
+	}
+	if fault.Latency > 0 {
+		select {
+		case <-time.After(time.Duration(fault.Latency)):
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+	if fault.Code != codes.OK {
+		return nil, status.Error(fault.Code, fault.Message)
+	}
+	return user, nil
+}
+
+func (s *Server) nextFault(user *User, method string) Fault {
+	for i, f := range user.Faults {
+		if f.Method != "" && f.Method != method {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				user.Faults = append(user.Faults[:i:i], user.Faults[i+1:]...)
+			}
+		}
+		return *f
+	}
+	return Fault{}
+}
+
+func NewServer(seed *Seed) *Server {
+	if seed.Users == nil {
+		seed.Users = map[string]*User{}
+	}
+	return &Server{seed: seed}
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var clientRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "fraud_scoring_grpc_client_requests_total",
+	Help: "User transactions calls per method and status code, including retries and circuit breaker rejections.",
+}, []string{"method", "code"})
+
+var clientLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "fraud_scoring_grpc_client_request_duration_seconds",
+	Help:    "Latency of user transactions calls per method, including retries.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"method"})
+
+var serverRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "fraud_scoring_grpc_server_requests_total",
+	Help: "Calls served by the gRPC server per method and status code.",
+}, []string{"method", "code"})
+
+var serverLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "fraud_scoring_grpc_server_request_duration_seconds",
+	Help:    "Latency of the calls served by the gRPC server per method, streams are measured until they end.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"method"})
+
+// clientMetricsInterceptor records every call as seen by the caller, it must be the outermost interceptor.
+func clientMetricsInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		clientRequests.WithLabelValues(method, status.Code(err).String()).Inc()
+		clientLatency.WithLabelValues(method).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+func serverMetricsUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		res, err := handler(ctx, req)
+		observeServerCall(info.FullMethod, start, err)
+		return res, err
+	}
+}
+
+func serverMetricsStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observeServerCall(info.FullMethod, start, err)
+		return err
+	}
+}
+
+func observeServerCall(method string, start time.Time, err error) {
+	serverRequests.WithLabelValues(method, status.Code(err).String()).Inc()
+	serverLatency.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
@@ -1,24 +1,182 @@
 package api
 
 import (
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"context"
+	"encoding/json"
+	"fmt"
+	"fraud-scoring/internal/infra/env"
 	"os"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/keepalive"
 )
 
 type UserTransactionsConfig struct {
 	Host string
+	// ConnectTimeout bounds the connectivity check made at startup. Zero disables the check.
+	ConnectTimeout time.Duration
+	Retry          RetryConfig
+	Keepalive      KeepaliveConfig
+	Breaker        BreakerConfig
+	TLS            TLSConfig
+	Token          TokenConfig
+	LoadBalancing  LoadBalancingConfig
+}
+
+type RetryConfig struct {
+	// MaxAttempts includes the original call, gRPC caps it at 5.
+	MaxAttempts       int
+	InitialBackoff    time.Duration
+	MaxBackoff        time.Duration
+	BackoffMultiplier float64
+}
+
+type KeepaliveConfig struct {
+	Time                time.Duration
+	Timeout             time.Duration
+	PermitWithoutStream bool
 }
 
-func NewUserTransactionGrpc(config *UserTransactionsConfig) UserTransactionsServiceClient {
-	conn, err := grpc.Dial(config.Host, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// serviceConfig retries the idempotent read RPCs of UserTransactionsService. gRPC applies a random jitter to every
+// backoff, so replicas recovering from an outage are not hit by synchronized retries.
+func (c *UserTransactionsConfig) serviceConfig() string {
+	sc := map[string]any{
+		"loadBalancingConfig": []any{c.loadBalancingPolicy()},
+		"methodConfig": []any{map[string]any{
+			"name": []any{
+				map[string]string{"service": "user.UserTransactionsService", "method": "GetUserMonthAverage"},
+				map[string]string{"service": "user.UserTransactionsService", "method": "GetLastUserTransaction"},
+			},
+			"retryPolicy": map[string]any{
+				"maxAttempts":          c.Retry.MaxAttempts,
+				"initialBackoff":       seconds(c.Retry.InitialBackoff),
+				"maxBackoff":           seconds(c.Retry.MaxBackoff),
+				"backoffMultiplier":    c.Retry.BackoffMultiplier,
+				"retryableStatusCodes": []string{"UNAVAILABLE", "RESOURCE_EXHAUSTED"},
+			},
+		}},
+	}
+	if c.LoadBalancing.HealthCheck {
+		sc["healthCheckConfig"] = map[string]string{"serviceName": c.LoadBalancing.HealthCheckService}
+	}
+	b, _ := json.Marshal(sc)
+	return string(b)
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
+
+func NewUserTransactionsConn(config *UserTransactionsConfig, breakers *CircuitBreakers, log *zap.Logger) (*grpc.ClientConn, error) {
+	creds, err := config.transportCredentials(log)
+	if err != nil {
+		return nil, err
+	}
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultServiceConfig(config.serviceConfig()),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                config.Keepalive.Time,
+			Timeout:             config.Keepalive.Timeout,
+			PermitWithoutStream: config.Keepalive.PermitWithoutStream,
+		}),
+		grpc.WithChainUnaryInterceptor(clientMetricsInterceptor(), breakers.UnaryClientInterceptor(), backendMetricsInterceptor(config.Host)),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	}
+	token, err := config.perRPCCredentials()
 	if err != nil {
+		return nil, err
+	}
+	if token != nil {
+		opts = append(opts, grpc.WithPerRPCCredentials(token))
+	}
+	target, resolverOpts := config.target()
+	conn, err := grpc.Dial(target, append(opts, resolverOpts...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user transactions client: %w", err)
+	}
+	if config.ConnectTimeout > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), config.ConnectTimeout)
+		defer cancel()
+		if err := awaitReady(ctx, conn); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("user transactions service %q is not reachable: %w", target, err)
+		}
+		log.Info("connected to user transactions service", zap.String("target", target))
+	}
+	return conn, nil
+}
 
+// awaitReady blocks until conn is ready to serve RPCs or ctx is done.
+func awaitReady(ctx context.Context, conn *grpc.ClientConn) error {
+	conn.Connect()
+	for {
+		state := conn.GetState()
+		if state == connectivity.Ready {
+			return nil
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			return ctx.Err()
+		}
 	}
-	client := NewUserTransactionsServiceClient(conn)
-	return client
+}
+
+func NewUserTransactionGrpc(conn *grpc.ClientConn) UserTransactionsServiceClient {
+	return NewUserTransactionsServiceClient(conn)
 }
 
 func NewUserTransactionsConfig() *UserTransactionsConfig {
-	return &UserTransactionsConfig{Host: os.Getenv("USER_TRANSACTIONS_HOST")}
+	return &UserTransactionsConfig{
+		Host:           os.Getenv("USER_TRANSACTIONS_HOST"),
+		ConnectTimeout: env.Duration("USER_TRANSACTIONS_CONNECT_TIMEOUT", 10*time.Second),
+		Retry: RetryConfig{
+			MaxAttempts:       env.Int("USER_TRANSACTIONS_RETRY_MAX_ATTEMPTS", 3),
+			InitialBackoff:    env.Duration("USER_TRANSACTIONS_RETRY_INITIAL_BACKOFF", 100*time.Millisecond),
+			MaxBackoff:        env.Duration("USER_TRANSACTIONS_RETRY_MAX_BACKOFF", time.Second),
+			BackoffMultiplier: env.Float("USER_TRANSACTIONS_RETRY_BACKOFF_MULTIPLIER", 2),
+		},
+		Keepalive: KeepaliveConfig{
+			Time:                env.Duration("USER_TRANSACTIONS_KEEPALIVE_TIME", time.Minute),
+			Timeout:             env.Duration("USER_TRANSACTIONS_KEEPALIVE_TIMEOUT", 20*time.Second),
+			PermitWithoutStream: env.Bool("USER_TRANSACTIONS_KEEPALIVE_PERMIT_WITHOUT_STREAM", false),
+		},
+		Breaker: BreakerConfig{
+			FailureThreshold: env.Int("USER_TRANSACTIONS_BREAKER_FAILURE_THRESHOLD", 5),
+			OpenTimeout:      env.Duration("USER_TRANSACTIONS_BREAKER_OPEN_TIMEOUT", 30*time.Second),
+		},
+		TLS: TLSConfig{
+			Enabled:    env.Bool("USER_TRANSACTIONS_TLS_ENABLED", false),
+			CAFile:     os.Getenv("USER_TRANSACTIONS_TLS_CA_FILE"),
+			CertFile:   os.Getenv("USER_TRANSACTIONS_TLS_CERT_FILE"),
+			KeyFile:    os.Getenv("USER_TRANSACTIONS_TLS_KEY_FILE"),
+			ServerName: os.Getenv("USER_TRANSACTIONS_TLS_SERVER_NAME"),
+		},
+		Token: TokenConfig{
+			Token:         os.Getenv("USER_TRANSACTIONS_TOKEN"),
+			File:          os.Getenv("USER_TRANSACTIONS_TOKEN_FILE"),
+			AllowInsecure: env.Bool("USER_TRANSACTIONS_TOKEN_ALLOW_INSECURE", false),
+		},
+		LoadBalancing: LoadBalancingConfig{
+			Endpoints:          env.Strings("USER_TRANSACTIONS_ENDPOINTS", nil),
+			Discovery:          env.String("USER_TRANSACTIONS_DISCOVERY", DiscoveryStatic),
+			Policy:             env.String("USER_TRANSACTIONS_LB_POLICY", BalancerRoundRobin),
+			HealthCheck:        env.Bool("USER_TRANSACTIONS_HEALTH_CHECK", true),
+			HealthCheckService: os.Getenv("USER_TRANSACTIONS_HEALTH_CHECK_SERVICE"),
+		},
+	}
+}
+
+// CheckConnection fails while conn cannot reach any user transactions backend. An idle connection passes, it
+// reconnects on the next call.
+func CheckConnection(conn *grpc.ClientConn) error {
+	switch state := conn.GetState(); state {
+	case connectivity.TransientFailure, connectivity.Shutdown:
+		return fmt.Errorf("user transactions connection is in state %s", state)
+	}
+	return nil
 }
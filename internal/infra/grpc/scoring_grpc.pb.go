@@ -0,0 +1,183 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.25.2
+// source: scoring.proto
+
+package api
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ScoringService_ScoreTransaction_FullMethodName  = "/fraud.ScoringService/ScoreTransaction"
+	ScoringService_ScoreTransactions_FullMethodName = "/fraud.ScoringService/ScoreTransactions"
+)
+
+// ScoringServiceClient is the client API for ScoringService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ScoringServiceClient interface {
+	// Scores a transaction and returns its scorecard and decision
+	ScoreTransaction(ctx context.Context, in *ScoreTransactionRequest, opts ...grpc.CallOption) (*ScoreTransactionResponse, error)
+	// Scores every transaction sent on the stream, answering in the order they were sent
+	ScoreTransactions(ctx context.Context, opts ...grpc.CallOption) (ScoringService_ScoreTransactionsClient, error)
+}
+
+type scoringServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewScoringServiceClient(cc grpc.ClientConnInterface) ScoringServiceClient {
+	return &scoringServiceClient{cc}
+}
+
+func (c *scoringServiceClient) ScoreTransaction(ctx context.Context, in *ScoreTransactionRequest, opts ...grpc.CallOption) (*ScoreTransactionResponse, error) {
+	out := new(ScoreTransactionResponse)
+	err := c.cc.Invoke(ctx, ScoringService_ScoreTransaction_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scoringServiceClient) ScoreTransactions(ctx context.Context, opts ...grpc.CallOption) (ScoringService_ScoreTransactionsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ScoringService_ServiceDesc.Streams[0], ScoringService_ScoreTransactions_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &scoringServiceScoreTransactionsClient{stream}
+	return x, nil
+}
+
+type ScoringService_ScoreTransactionsClient interface {
+	Send(*ScoreTransactionRequest) error
+	Recv() (*ScoreTransactionResponse, error)
+	grpc.ClientStream
+}
+
+type scoringServiceScoreTransactionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *scoringServiceScoreTransactionsClient) Send(m *ScoreTransactionRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *scoringServiceScoreTransactionsClient) Recv() (*ScoreTransactionResponse, error) {
+	m := new(ScoreTransactionResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ScoringServiceServer is the server API for ScoringService service.
+// All implementations must embed UnimplementedScoringServiceServer
+// for forward compatibility
+type ScoringServiceServer interface {
+	// Scores a transaction and returns its scorecard and decision
+	ScoreTransaction(context.Context, *ScoreTransactionRequest) (*ScoreTransactionResponse, error)
+	// Scores every transaction sent on the stream, answering in the order they were sent
+	ScoreTransactions(ScoringService_ScoreTransactionsServer) error
+	mustEmbedUnimplementedScoringServiceServer()
+}
+
+// UnimplementedScoringServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedScoringServiceServer struct {
+}
+
+func (UnimplementedScoringServiceServer) ScoreTransaction(context.Context, *ScoreTransactionRequest) (*ScoreTransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScoreTransaction not implemented")
+}
+func (UnimplementedScoringServiceServer) ScoreTransactions(ScoringService_ScoreTransactionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ScoreTransactions not implemented")
+}
+func (UnimplementedScoringServiceServer) mustEmbedUnimplementedScoringServiceServer() {}
+
+// UnsafeScoringServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ScoringServiceServer will
+// result in compilation errors.
+type UnsafeScoringServiceServer interface {
+	mustEmbedUnimplementedScoringServiceServer()
+}
+
+func RegisterScoringServiceServer(s grpc.ServiceRegistrar, srv ScoringServiceServer) {
+	s.RegisterService(&ScoringService_ServiceDesc, srv)
+}
+
+func _ScoringService_ScoreTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScoreTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScoringServiceServer).ScoreTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScoringService_ScoreTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScoringServiceServer).ScoreTransaction(ctx, req.(*ScoreTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScoringService_ScoreTransactions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ScoringServiceServer).ScoreTransactions(&scoringServiceScoreTransactionsServer{stream})
+}
+
+type ScoringService_ScoreTransactionsServer interface {
+	Send(*ScoreTransactionResponse) error
+	Recv() (*ScoreTransactionRequest, error)
+	grpc.ServerStream
+}
+
+type scoringServiceScoreTransactionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *scoringServiceScoreTransactionsServer) Send(m *ScoreTransactionResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *scoringServiceScoreTransactionsServer) Recv() (*ScoreTransactionRequest, error) {
+	m := new(ScoreTransactionRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ScoringService_ServiceDesc is the grpc.ServiceDesc for ScoringService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ScoringService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "fraud.ScoringService",
+	HandlerType: (*ScoringServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ScoreTransaction",
+			Handler:    _ScoringService_ScoreTransaction_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ScoreTransactions",
+			Handler:       _ScoringService_ScoreTransactions_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "scoring.proto",
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"fraud-scoring/internal/infra/env"
+	"net"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+type ServerConfig struct {
+	Port int
+}
+
+// ScoringServer serves ScoringService along with the standard gRPC health and reflection services.
+type ScoringServer struct {
+	config *ServerConfig
+	srv    *grpc.Server
+	health *health.Server
+	log    *zap.Logger
+}
+
+// Serve listens on the configured port and blocks until the server stops.
+func (ss *ScoringServer) Serve() error {
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", ss.config.Port))
+	if err != nil {
+		return fmt.Errorf("failed to listen on grpc port %d: %w", ss.config.Port, err)
+	}
+	return ss.ServeListener(lis)
+}
+
+func (ss *ScoringServer) ServeListener(lis net.Listener) error {
+	ss.log.Info("grpc server started", zap.String("addr", lis.Addr().String()))
+	return ss.srv.Serve(lis)
+}
+
+// GracefulStop reports the services as not serving and waits for pending RPCs to finish.
+func (ss *ScoringServer) GracefulStop() {
+	ss.health.Shutdown()
+	ss.srv.GracefulStop()
+}
+
+// Shutdown stops the server gracefully, the RPCs still pending when ctx is done are canceled.
+func (ss *ScoringServer) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		ss.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		ss.srv.Stop()
+		<-stopped
+		return fmt.Errorf("grpc server did not stop in time: %w", ctx.Err())
+	}
+}
+
+func NewScoringServer(config *ServerConfig, scoring ScoringServiceServer, log *zap.Logger) *ScoringServer {
+	srv := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(serverMetricsUnaryInterceptor()),
+		grpc.ChainStreamInterceptor(serverMetricsStreamInterceptor()),
+	)
+	hs := health.NewServer()
+	RegisterScoringServiceServer(srv, scoring)
+	healthpb.RegisterHealthServer(srv, hs)
+	reflection.Register(srv)
+	hs.SetServingStatus(ScoringService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	return &ScoringServer{config: config, srv: srv, health: hs, log: log}
+}
+
+func NewServerConfig() *ServerConfig {
+	return &ServerConfig{Port: env.Int("GRPC_PORT", 50051)}
+}
@@ -0,0 +1,114 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fraud-scoring/internal/infra/env"
+	"sync"
+	"time"
+)
+
+const (
+	StatusHealthy   = "healthy"
+	StatusUnhealthy = "unhealthy"
+)
+
+// Check reports whether a dependency is usable, it returns nil when it is.
+type Check func(ctx context.Context) error
+
+type Config struct {
+	// Timeout bounds every run of the checks, a check still running when it expires fails.
+	Timeout time.Duration
+	Version string
+}
+
+// Report is the outcome of the checks of one probe, grouped by dependency.
+type Report struct {
+	Status       string
+	Version      string
+	Dependencies map[string]string
+	// Errors holds the failures of the unhealthy dependencies.
+	Errors map[string]string
+}
+
+func (r *Report) Healthy() bool {
+	return r.Status == StatusHealthy
+}
+
+type probe struct {
+	dependency string
+	check      Check
+}
+
+// Checker runs the liveness and readiness checks registered by the dependencies of the service.
+type Checker struct {
+	config    *Config
+	liveness  []probe
+	readiness []probe
+}
+
+// Liveness registers a check failing when the service cannot recover by itself and should be restarted.
+func (c *Checker) Liveness(dependency string, check Check) {
+	c.liveness = append(c.liveness, probe{dependency: dependency, check: check})
+}
+
+// Readiness registers a check failing when the service cannot do its work for now.
+func (c *Checker) Readiness(dependency string, check Check) {
+	c.readiness = append(c.readiness, probe{dependency: dependency, check: check})
+}
+
+func (c *Checker) Live(ctx context.Context) *Report {
+	return c.run(ctx, c.liveness)
+}
+
+func (c *Checker) Ready(ctx context.Context) *Report {
+	return c.run(ctx, c.readiness)
+}
+
+// run runs the checks concurrently, a dependency is healthy when all of its checks pass.
+func (c *Checker) run(ctx context.Context, probes []probe) *Report {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	errs := make([]error, len(probes))
+	var wg sync.WaitGroup
+	for i, p := range probes {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			errs[i] = check(ctx)
+		}(i, p.check)
+	}
+	wg.Wait()
+
+	failures := map[string][]error{}
+	report := &Report{Status: StatusHealthy, Version: c.config.Version, Dependencies: map[string]string{}}
+	for i, p := range probes {
+		if _, ok := report.Dependencies[p.dependency]; !ok {
+			report.Dependencies[p.dependency] = StatusHealthy
+		}
+		if errs[i] != nil {
+			report.Dependencies[p.dependency] = StatusUnhealthy
+			report.Status = StatusUnhealthy
+			failures[p.dependency] = append(failures[p.dependency], errs[i])
+		}
+	}
+	if len(failures) > 0 {
+		report.Errors = map[string]string{}
+		for dependency, errs := range failures {
+			report.Errors[dependency] = errors.Join(errs...).Error()
+		}
+	}
+	return report
+}
+
+func NewChecker(config *Config) *Checker {
+	return &Checker{config: config}
+}
+
+func NewConfig() *Config {
+	return &Config{
+		Timeout: env.Duration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+		Version: env.String("SERVICE_VERSION", "dev"),
+	}
+}
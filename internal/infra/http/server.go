@@ -0,0 +1,68 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fraud-scoring/internal/infra/env"
+	"net"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type ServerConfig struct {
+	Port         int
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+}
+
+// Server serves the REST API.
+type Server struct {
+	config *ServerConfig
+	srv    *http.Server
+	log    *zap.Logger
+}
+
+// Serve listens on the configured port and blocks until the server is shut down.
+func (s *Server) Serve() error {
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", s.config.Port))
+	if err != nil {
+		return fmt.Errorf("failed to listen on http port %d: %w", s.config.Port, err)
+	}
+	return s.ServeListener(lis)
+}
+
+func (s *Server) ServeListener(lis net.Listener) error {
+	s.log.Info("http server started", zap.String("addr", lis.Addr().String()))
+	if err := s.srv.Serve(lis); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown stops accepting requests and waits for the pending ones until ctx is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.srv.Shutdown(ctx)
+}
+
+func NewServer(config *ServerConfig, handler http.Handler, log *zap.Logger) *Server {
+	return &Server{
+		config: config,
+		srv: &http.Server{
+			Handler:      handler,
+			ReadTimeout:  config.ReadTimeout,
+			WriteTimeout: config.WriteTimeout,
+		},
+		log: log,
+	}
+}
+
+func NewServerConfig() *ServerConfig {
+	return &ServerConfig{
+		Port:         env.Int("HTTP_PORT", 8080),
+		ReadTimeout:  env.Duration("HTTP_READ_TIMEOUT", 10*time.Second),
+		WriteTimeout: env.Duration("HTTP_WRITE_TIMEOUT", 30*time.Second),
+	}
+}
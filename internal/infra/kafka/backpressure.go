@@ -0,0 +1,197 @@
+package kafka
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var consumerPaused = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "fraud_scoring_consumer_paused",
+	Help: "1 while the consumption is paused because a dependency of the handlers is down, 0 otherwise.",
+})
+
+var consumerPauses = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "fraud_scoring_consumer_pauses_total",
+	Help: "Times the consumption was paused, per dependency found down.",
+}, []string{"dependency"})
+
+var consumerLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "fraud_scoring_consumer_lag",
+	Help: "Messages of an assigned partition not consumed yet: its newest offset minus the next offset to commit.",
+}, []string{"topic", "partition"})
+
+// Dependency fails while a dependency the handlers can't do without is down.
+type Dependency func(ctx context.Context) error
+
+type dependency struct {
+	name  string
+	check Dependency
+}
+
+// backpressure pauses the consumption while a dependency is down, handling events then would only fail them through
+// the retry topics. The dependencies are checked every interval, the consumption resumes once they all answer again.
+type backpressure struct {
+	interval     time.Duration
+	mu           sync.Mutex
+	dependencies []dependency
+	// resumed is closed while the consumption runs, and replaced by an open channel while it is paused.
+	resumed chan struct{}
+	log     *zap.Logger
+}
+
+func newBackpressure(interval time.Duration, log *zap.Logger) *backpressure {
+	resumed := make(chan struct{})
+	close(resumed)
+	return &backpressure{interval: interval, resumed: resumed, log: log}
+}
+
+func (bp *backpressure) dependsOn(name string, check Dependency) {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	bp.dependencies = append(bp.dependencies, dependency{name: name, check: check})
+}
+
+// update checks the dependencies and pauses or resumes the consumption accordingly. It returns whether the
+// consumption is paused, and whether it just changed.
+func (bp *backpressure) update(ctx context.Context) (paused, changed bool) {
+	bp.mu.Lock()
+	dependencies := bp.dependencies
+	bp.mu.Unlock()
+	ctx, cancel := context.WithTimeout(ctx, bp.interval)
+	defer cancel()
+	for _, d := range dependencies {
+		if err := d.check(ctx); err != nil {
+			return true, bp.pause(d.name, err)
+		}
+	}
+	return false, bp.resume()
+}
+
+func (bp *backpressure) pause(name string, cause error) bool {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	select {
+	case <-bp.resumed:
+	default:
+		return false
+	}
+	bp.resumed = make(chan struct{})
+	consumerPaused.Set(1)
+	consumerPauses.WithLabelValues(name).Inc()
+	bp.log.Warn("pausing kafka consumption, a dependency is down", zap.String("dependency", name), zap.Error(cause))
+	return true
+}
+
+func (bp *backpressure) resume() bool {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	select {
+	case <-bp.resumed:
+		return false
+	default:
+	}
+	close(bp.resumed)
+	consumerPaused.Set(0)
+	bp.log.Info("resuming kafka consumption, the dependencies are back")
+	return true
+}
+
+func (bp *backpressure) paused() bool {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	select {
+	case <-bp.resumed:
+		return false
+	default:
+		return true
+	}
+}
+
+// await returns once the consumption is not paused, false when ctx is done first.
+func (bp *backpressure) await(ctx context.Context) bool {
+	bp.mu.Lock()
+	resumed := bp.resumed
+	bp.mu.Unlock()
+	select {
+	case <-resumed:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// topicPartition identifies a claim.
+type topicPartition struct {
+	topic     string
+	partition int32
+}
+
+// monitor pauses the fetching of the assigned partitions while the backpressure says so and resumes it after, then
+// reports their lag, every backpressure interval until ctx is done. Partitions assigned while paused are paused at
+// the next check. The retry partitions waiting for their next message to be due stay paused when the others resume.
+func (cg *ConsumerGroup) monitor(ctx context.Context) {
+	ticker := time.NewTicker(cg.backpressure.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		paused, changed := cg.backpressure.update(ctx)
+		if paused {
+			cg.group.PauseAll()
+		} else if changed {
+			cg.group.ResumeAll()
+			if delayed := cg.delayedPartitions(); len(delayed) > 0 {
+				cg.group.Pause(delayed)
+			}
+		}
+		cg.reportLag()
+	}
+}
+
+func (cg *ConsumerGroup) claim(tp topicPartition, offsets *partitionOffsets) {
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+	cg.claims[tp] = offsets
+}
+
+func (cg *ConsumerGroup) release(tp topicPartition) {
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+	delete(cg.claims, tp)
+	consumerLag.DeleteLabelValues(tp.topic, strconv.Itoa(int(tp.partition)))
+}
+
+// reportLag sets the lag of the claimed partitions whose position is known, from their newest offset.
+func (cg *ConsumerGroup) reportLag() {
+	cg.mu.Lock()
+	claims := make(map[topicPartition]*partitionOffsets, len(cg.claims))
+	for tp, offsets := range cg.claims {
+		claims[tp] = offsets
+	}
+	cg.mu.Unlock()
+	for tp, offsets := range claims {
+		next := offsets.next.Load()
+		if next < 0 {
+			continue
+		}
+		newest, err := cg.client.GetOffset(tp.topic, tp.partition, sarama.OffsetNewest)
+		if err != nil {
+			cg.log.Debug("failed to get the newest offset of a partition",
+				zap.String("topic", tp.topic),
+				zap.Int32("partition", tp.partition),
+				zap.Error(err))
+			continue
+		}
+		consumerLag.WithLabelValues(tp.topic, strconv.Itoa(int(tp.partition))).Set(float64(max(newest-next, 0)))
+	}
+}
@@ -0,0 +1,68 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func TestBackpressure_PausesWhileADependencyIsDown(t *testing.T) {
+	bp := newBackpressure(time.Second, zaptest.NewLogger(t))
+	var down atomic.Bool
+	bp.dependsOn("producer", func(context.Context) error {
+		if down.Load() {
+			return errors.New("kafka topic is not reachable")
+		}
+		return nil
+	})
+
+	if paused, changed := bp.update(context.Background()); paused || changed {
+		t.Fatalf("Expected the consumption to run, got paused=%v changed=%v", paused, changed)
+	}
+	down.Store(true)
+	if paused, changed := bp.update(context.Background()); !paused || !changed {
+		t.Fatalf("Expected the consumption to pause, got paused=%v changed=%v", paused, changed)
+	}
+	if paused, changed := bp.update(context.Background()); !paused || changed {
+		t.Fatalf("Expected the consumption to stay paused, got paused=%v changed=%v", paused, changed)
+	}
+
+	awaited := make(chan bool)
+	go func() {
+		awaited <- bp.await(context.Background())
+	}()
+	select {
+	case <-awaited:
+		t.Fatal("Expected the dispatch to wait while paused")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	down.Store(false)
+	if paused, changed := bp.update(context.Background()); paused || !changed {
+		t.Fatalf("Expected the consumption to resume, got paused=%v changed=%v", paused, changed)
+	}
+	select {
+	case ok := <-awaited:
+		if !ok {
+			t.Error("Expected the dispatch to go on once resumed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the dispatch to go on once resumed")
+	}
+}
+
+func TestBackpressure_AwaitGivesUpWithTheSession(t *testing.T) {
+	bp := newBackpressure(time.Second, zaptest.NewLogger(t))
+	bp.dependsOn("grpc", func(context.Context) error { return errors.New("circuit breaker is open") })
+	bp.update(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if bp.await(ctx) {
+		t.Error("Expected await to give up once the session ended")
+	}
+}
@@ -0,0 +1,407 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"go.uber.org/zap"
+)
+
+// CloudEventHandler handles one event read from Kafka. When it fails, the message is forwarded to a retry topic or to
+// the dead-letter topic before its offset is committed, see HandlerError.
+type CloudEventHandler func(ctx context.Context, event cloudevents.Event) error
+
+// ConsumerGroup reads CloudEvents from the payment processing topic and its retry topics as a member of the
+// configured consumer group, and hands them to a pool of workers.
+// It keeps track of its group membership and of the messages being handled, so health probes can tell whether the
+// service is consuming.
+type ConsumerGroup struct {
+	group        sarama.ConsumerGroup
+	topics       []string
+	stallTimeout time.Duration
+	workerCount  int
+	queueSize    int
+	handler      CloudEventHandler
+	key          OrderingKey
+	workers      *workerPool
+	retry        *Retrier
+	dlq          *DeadLetterQueue
+	client       sarama.Client
+	backpressure *backpressure
+	log          *zap.Logger
+
+	stopped atomic.Bool
+	member  atomic.Bool
+	// handling is the context of the handlers, it outlives the sessions so the messages being handled when the
+	// consumption stops are finished rather than canceled, until Drain gives up on them.
+	handling context.Context
+	abort    context.CancelFunc
+	done     chan struct{}
+
+	mu       sync.Mutex
+	inFlight map[messagePosition]time.Time
+	claims   map[topicPartition]*partitionOffsets
+	delayed  map[topicPartition]struct{}
+}
+
+type messagePosition struct {
+	topic     string
+	partition int32
+	offset    int64
+}
+
+// StartReceiver joins the consumer group and hands every event of the assigned partitions to fn until ctx is done or
+// the group is closed. The events are handled concurrently, except the ones with the same ordering key, handled in the
+// order they were read. A message is settled once fn handled its event, or once it was forwarded to the retry topics
+// or, when its failure is terminal or its retries are exhausted, to the dead-letter topic. A message that is not a
+// cloud event is dead-lettered as invalid_event without reaching fn. Whatever their key, the offset of a message is
+// committed only once it and all the messages before it in its partition are settled. When ctx is done, the messages
+// being handled are finished and their offsets committed before it returns; the ones still queued when their session
+// ends, or whose handling was cut short, are read again by the next owner of their partition.
+// While a dependency registered with DependsOn is down, the consumption is paused.
+func (cg *ConsumerGroup) StartReceiver(ctx context.Context, fn CloudEventHandler, key OrderingKey) error {
+	cg.handler = fn
+	cg.key = key
+	cg.workers = cg.startWorkers()
+	defer close(cg.done)
+	defer cg.stopped.Store(true)
+	defer cg.workers.stop()
+	monitoring, stopMonitoring := context.WithCancel(ctx)
+	defer stopMonitoring()
+	go cg.monitor(monitoring)
+	for {
+		if err := cg.group.Consume(ctx, cg.topics, cg); err != nil {
+			if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+				return nil
+			}
+			return fmt.Errorf("kafka consumer group stopped: %w", err)
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// Setup is called by sarama once the member has been assigned its partitions.
+func (cg *ConsumerGroup) Setup(session sarama.ConsumerGroupSession) error {
+	cg.member.Store(true)
+	cg.log.Info("joined kafka consumer group",
+		zap.String("member", session.MemberID()),
+		zap.Int32("generation", session.GenerationID()),
+		zap.Any("claims", session.Claims()))
+	return nil
+}
+
+// Cleanup is called by sarama when the session ends, before a rebalance or on shutdown.
+func (cg *ConsumerGroup) Cleanup(session sarama.ConsumerGroupSession) error {
+	cg.member.Store(false)
+	cg.log.Info("left kafka consumer group", zap.String("member", session.MemberID()))
+	return nil
+}
+
+// ConsumeClaim dispatches the messages of a partition to the workers. It returns once the messages it dispatched are
+// settled, so their offsets are committed by this session.
+func (cg *ConsumerGroup) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	offsets := &partitionOffsets{session: session}
+	offsets.next.Store(claim.InitialOffset())
+	defer offsets.wait()
+	tp := topicPartition{topic: claim.Topic(), partition: claim.Partition()}
+	cg.claim(tp, offsets)
+	defer cg.release(tp)
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			if !cg.hold(session, msg) || !cg.backpressure.await(session.Context()) {
+				// The session is ending, the message is read again by the next owner of the partition.
+				return nil
+			}
+			j := cg.read(session, msg, offsets)
+			offsets.add(j)
+			if !cg.workers.dispatch(session.Context(), cg.keyOf(j), j) {
+				offsets.settle(j, false)
+				return nil
+			}
+		case <-session.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (cg *ConsumerGroup) startWorkers() *workerPool {
+	wp := &workerPool{queues: make([]chan *job, cg.workerCount)}
+	for i := range wp.queues {
+		queue := make(chan *job, cg.queueSize)
+		wp.queues[i] = queue
+		wp.wg.Add(1)
+		go func() {
+			defer wp.wg.Done()
+			cg.work(queue)
+		}()
+	}
+	return wp
+}
+
+// work handles the jobs of queue. The ones queued when their session ended are given up, another member owns their
+// partition by now.
+func (cg *ConsumerGroup) work(queue <-chan *job) {
+	for j := range queue {
+		eventsQueued.Dec()
+		consumed := false
+		if j.session.Context().Err() == nil {
+			consumed = cg.handle(j)
+		}
+		j.offsets.settle(j, consumed)
+	}
+}
+
+// read turns msg into a job, with the reason when it isn't a cloud event.
+func (cg *ConsumerGroup) read(session sarama.ConsumerGroupSession, msg *sarama.ConsumerMessage, offsets *partitionOffsets) *job {
+	j := &job{session: session, msg: msg, offsets: offsets}
+	event, err := binding.ToEvent(cg.handling, kafka_sarama.NewMessageFromConsumerMessage(msg))
+	if err != nil {
+		cg.log.Error("failed to read cloud event from kafka message",
+			zap.String("topic", msg.Topic),
+			zap.Int32("partition", msg.Partition),
+			zap.Int64("offset", msg.Offset),
+			zap.Error(err))
+		j.err = HandlerError{Class: "invalid_event", Err: err}
+		return j
+	}
+	j.event = event
+	return j
+}
+
+// keyOf is the ordering key of j. Without one, the messages with the same Kafka key are kept in order, and the ones
+// without key in the order of their partition.
+func (cg *ConsumerGroup) keyOf(j *job) string {
+	if j.event != nil && cg.key != nil {
+		if key := cg.key(*j.event); key != "" {
+			return key
+		}
+	}
+	if len(j.msg.Key) > 0 {
+		return string(j.msg.Key)
+	}
+	return j.msg.Topic + "/" + strconv.Itoa(int(j.msg.Partition))
+}
+
+// hold keeps a message of a retry topic until its delay is over. Its partition is paused meanwhile, so nothing more is
+// fetched from it, and resumed by a timer once the message is due. The messages of a retry partition all have the
+// same delay, so the ones behind it are not due before it. It returns false when the session ends first.
+func (cg *ConsumerGroup) hold(session sarama.ConsumerGroupSession, msg *sarama.ConsumerMessage) bool {
+	if session.Context().Err() != nil {
+		return false
+	}
+	delay := time.Until(notBefore(msg))
+	if delay <= 0 {
+		return true
+	}
+	tp := topicPartition{topic: msg.Topic, partition: msg.Partition}
+	cg.delay(tp)
+	due := make(chan struct{})
+	timer := time.AfterFunc(delay, func() {
+		cg.undelay(tp)
+		close(due)
+	})
+	select {
+	case <-due:
+		return true
+	case <-session.Context().Done():
+	case <-cg.handling.Done():
+	}
+	if timer.Stop() {
+		cg.undelay(tp)
+	}
+	return false
+}
+
+// delay pauses the fetching of a retry partition whose next message is not due yet.
+func (cg *ConsumerGroup) delay(tp topicPartition) {
+	cg.mu.Lock()
+	cg.delayed[tp] = struct{}{}
+	cg.mu.Unlock()
+	cg.group.Pause(map[string][]int32{tp.topic: {tp.partition}})
+}
+
+// undelay resumes the fetching of a retry partition once its next message is due, unless the whole consumption is
+// paused, it is resumed with the others then.
+func (cg *ConsumerGroup) undelay(tp topicPartition) {
+	cg.mu.Lock()
+	delete(cg.delayed, tp)
+	cg.mu.Unlock()
+	if !cg.backpressure.paused() {
+		cg.group.Resume(map[string][]int32{tp.topic: {tp.partition}})
+	}
+}
+
+// delayedPartitions are the retry partitions paused until their next message is due.
+func (cg *ConsumerGroup) delayedPartitions() map[string][]int32 {
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+	partitions := map[string][]int32{}
+	for tp := range cg.delayed {
+		partitions[tp.topic] = append(partitions[tp.topic], tp.partition)
+	}
+	return partitions
+}
+
+// handle hands the event of j to the handler and tells whether its message is consumed. When it can't be handled, it
+// is first sent to the retry topics if the failure is transient, then to the dead-letter topic. A message is left
+// unconsumed only when its handling was cut short, so it is read again.
+func (cg *ConsumerGroup) handle(j *job) bool {
+	ctx := cg.handling
+	pos := messagePosition{topic: j.msg.Topic, partition: j.msg.Partition, offset: j.msg.Offset}
+	cg.begin(pos)
+	defer cg.end(pos)
+	err := j.err
+	if err == nil {
+		err = cg.handler(ctx, *j.event)
+	}
+	if err != nil {
+		return ctx.Err() == nil && cg.forward(j.session, j.msg, err)
+	}
+	return true
+}
+
+// forward sends msg to the next retry topic, or to the dead-letter topic, trying until it succeeds or the session
+// ends. Meanwhile the partition doesn't move, and a long outage of these topics shows as a stalled consumer.
+func (cg *ConsumerGroup) forward(session sarama.ConsumerGroupSession, msg *sarama.ConsumerMessage, cause error) bool {
+	backoff := time.Second
+	for {
+		err := cg.send(msg, cause)
+		if err == nil {
+			return true
+		}
+		cg.log.Error("failed to forward failed message, retrying",
+			zap.String("topic", msg.Topic),
+			zap.Int32("partition", msg.Partition),
+			zap.Int64("offset", msg.Offset),
+			zap.Duration("backoff", backoff),
+			zap.Error(err))
+		select {
+		case <-time.After(backoff):
+		case <-session.Context().Done():
+			return false
+		case <-cg.handling.Done():
+			return false
+		}
+		backoff = min(2*backoff, 30*time.Second)
+	}
+}
+
+func (cg *ConsumerGroup) send(msg *sarama.ConsumerMessage, cause error) error {
+	if Retryable(cause) {
+		if sent, err := cg.retry.Send(msg, cause); sent || err != nil {
+			return err
+		}
+	}
+	return cg.dlq.Send(msg, cause)
+}
+
+func (cg *ConsumerGroup) begin(pos messagePosition) {
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+	cg.inFlight[pos] = time.Now()
+}
+
+func (cg *ConsumerGroup) end(pos messagePosition) {
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+	delete(cg.inFlight, pos)
+}
+
+// DependsOn pauses the consumption while check fails, rather than failing every event through the retry topics. The
+// fetching of the assigned partitions stops, and so does the dispatch of the messages already fetched, until every
+// dependency answers again.
+func (cg *ConsumerGroup) DependsOn(name string, check Dependency) {
+	cg.backpressure.dependsOn(name, check)
+}
+
+// Ready fails while the consumer has no partitions assigned, before the group is joined or during a rebalance.
+func (cg *ConsumerGroup) Ready(_ context.Context) error {
+	if !cg.member.Load() {
+		return errors.New("not a member of the kafka consumer group")
+	}
+	return nil
+}
+
+// Live fails when the consumption loop has stopped, or when a message has been in its handler for longer than the
+// stall timeout.
+func (cg *ConsumerGroup) Live(_ context.Context) error {
+	if cg.stopped.Load() {
+		return errors.New("kafka consumption loop has stopped")
+	}
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+	for pos, since := range cg.inFlight {
+		if elapsed := time.Since(since); elapsed > cg.stallTimeout {
+			return fmt.Errorf("kafka message %s/%d/%d has been in its handler for %s", pos.topic, pos.partition, pos.offset, elapsed.Round(time.Second))
+		}
+	}
+	return nil
+}
+
+// Drain waits for StartReceiver to return once its context is done. When ctx is done first, the messages still being
+// handled are canceled and Drain fails.
+func (cg *ConsumerGroup) Drain(ctx context.Context) error {
+	select {
+	case <-cg.done:
+		return nil
+	case <-ctx.Done():
+		cg.abort()
+		return fmt.Errorf("kafka consumer did not drain in time: %w", ctx.Err())
+	}
+}
+
+// Close leaves the consumer group and closes its client.
+func (cg *ConsumerGroup) Close() error {
+	cg.abort()
+	err := cg.group.Close()
+	if cerr := cg.client.Close(); cerr != nil && !errors.Is(cerr, sarama.ErrClosedClient) {
+		err = errors.Join(err, cerr)
+	}
+	return err
+}
+
+func NewConsumerGroup(sc *SaramaConfig, retry *Retrier, dlq *DeadLetterQueue, log *zap.Logger) (*ConsumerGroup, error) {
+	client, err := sarama.NewClient([]string{sc.Host}, newSaramaConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka consumer client: %w", err)
+	}
+	group, err := sarama.NewConsumerGroupFromClient(sc.GroupId, client)
+	if err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to create kafka consumer group: %w", err)
+	}
+	handling, abort := context.WithCancel(context.Background())
+	return &ConsumerGroup{
+		group:        group,
+		topics:       append([]string{sc.PaymentProcessingTopic}, retry.Topics()...),
+		stallTimeout: sc.StallTimeout,
+		workerCount:  sc.Workers,
+		queueSize:    sc.QueueSize,
+		retry:        retry,
+		dlq:          dlq,
+		client:       client,
+		backpressure: newBackpressure(sc.PauseCheckInterval, log),
+		log:          log,
+		handling:     handling,
+		abort:        abort,
+		done:         make(chan struct{}),
+		inFlight:     map[messagePosition]time.Time{},
+		claims:       map[topicPartition]*partitionOffsets{},
+		delayed:      map[topicPartition]struct{}{},
+	}, nil
+}
@@ -0,0 +1,334 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+// Headers added to the messages of the dead-letter topic, next to the headers of the original message.
+const (
+	headerPrefix            = "dlq-"
+	HeaderErrorClass        = headerPrefix + "error-class"
+	HeaderErrorMessage      = headerPrefix + "error-message"
+	HeaderErrorDetails      = headerPrefix + "error-details"
+	HeaderAttempts          = headerPrefix + "attempts"
+	HeaderOriginalTopic     = headerPrefix + "original-topic"
+	HeaderOriginalPartition = headerPrefix + "original-partition"
+	HeaderOriginalOffset    = headerPrefix + "original-offset"
+	HeaderOriginalTimestamp = headerPrefix + "original-timestamp"
+)
+
+// classUnknown is the class of the handler errors that don't say what went wrong.
+const classUnknown = "unknown"
+
+var deadLettered = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "fraud_scoring_events_dead_lettered_total",
+	Help: "Messages sent to the dead-letter topic per error class.",
+}, []string{"class"})
+
+// HandlerError is returned by a CloudEventHandler for an event it can't process. Class names the failure in the
+// dead-letter headers, such as invalid_data or history_unavailable. A Retryable failure is transient, the event goes
+// through the retry topics before being dead-lettered. Details, such as the fields of an invalid event, are added to
+// the dead-letter headers as JSON.
+type HandlerError struct {
+	Class     string
+	Retryable bool
+	Err       error
+	Details   any
+}
+
+func (he HandlerError) Error() string {
+	return he.Class + ": " + he.Err.Error()
+}
+
+func (he HandlerError) Unwrap() error {
+	return he.Err
+}
+
+// Attempt is one failed try at handling a message. A message carries all its attempts, so one that fails again after
+// being redriven keeps the history of its earlier failures.
+type Attempt struct {
+	At      time.Time `json:"at"`
+	Topic   string    `json:"topic"`
+	Class   string    `json:"class"`
+	Message string    `json:"message"`
+}
+
+// DeadLetter is a message read from the dead-letter topic.
+type DeadLetter struct {
+	Partition         int32             `json:"partition"`
+	Offset            int64             `json:"offset"`
+	Key               string            `json:"key,omitempty"`
+	Class             string            `json:"class"`
+	Message           string            `json:"message"`
+	Details           json.RawMessage   `json:"details,omitempty"`
+	Attempts          []Attempt         `json:"attempts"`
+	OriginalTopic     string            `json:"originalTopic"`
+	OriginalPartition int32             `json:"originalPartition"`
+	OriginalOffset    int64             `json:"originalOffset"`
+	OriginalTimestamp time.Time         `json:"originalTimestamp"`
+	Headers           map[string]string `json:"headers,omitempty"`
+	Value             string            `json:"value"`
+
+	msg *sarama.ConsumerMessage
+}
+
+// DeadLetterQueue parks the messages that could not be handled on the dead-letter topic, with the reason in their
+// headers, so they can be inspected and redriven instead of being lost.
+type DeadLetterQueue struct {
+	producer *Producer
+	topic    string
+	log      *zap.Logger
+}
+
+// Send publishes msg to the dead-letter topic, adding err to its attempts. The original headers locate it where it was
+// first read, before any retry, so it can be found and replayed from there.
+func (dlq *DeadLetterQueue) Send(msg *sarama.ConsumerMessage, err error) error {
+	class := errorClass(err)
+	attempts, aerr := encodeAttempts(msg, err)
+	if aerr != nil {
+		return aerr
+	}
+	headers := append(originalHeaders(msg.Headers),
+		sarama.RecordHeader{Key: []byte(HeaderErrorClass), Value: []byte(class)},
+		sarama.RecordHeader{Key: []byte(HeaderErrorMessage), Value: []byte(err.Error())},
+		sarama.RecordHeader{Key: []byte(HeaderAttempts), Value: attempts},
+	)
+	headers = append(headers, origin(msg)...)
+	if details := errorDetails(err); details != nil {
+		headers = append(headers, sarama.RecordHeader{Key: []byte(HeaderErrorDetails), Value: details})
+	}
+	if err := dlq.producer.SendMessage(forward(dlq.topic, msg, headers)); err != nil {
+		return fmt.Errorf("failed to send message to dead-letter topic %s: %w", dlq.topic, err)
+	}
+	deadLettered.WithLabelValues(class).Inc()
+	dlq.log.Warn("message sent to dead-letter topic",
+		zap.String("topic", msg.Topic),
+		zap.Int32("partition", msg.Partition),
+		zap.Int64("offset", msg.Offset),
+		zap.String("class", class),
+		zap.Error(err))
+	return nil
+}
+
+// Read calls fn with the messages of the dead-letter topic, partition by partition, up to the last message of each
+// partition when Read started. It stops at the first error of fn.
+func (dlq *DeadLetterQueue) Read(ctx context.Context, fn func(*DeadLetter) error) error {
+	client := dlq.producer.client
+	partitions, err := client.Partitions(dlq.topic)
+	if err != nil {
+		return fmt.Errorf("failed to list partitions of %s: %w", dlq.topic, err)
+	}
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		return fmt.Errorf("failed to create kafka consumer: %w", err)
+	}
+	defer consumer.Close()
+	for _, partition := range partitions {
+		if err := dlq.readPartition(ctx, consumer, partition, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (dlq *DeadLetterQueue) readPartition(ctx context.Context, consumer sarama.Consumer, partition int32, fn func(*DeadLetter) error) error {
+	client := dlq.producer.client
+	oldest, err := client.GetOffset(dlq.topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return fmt.Errorf("failed to read oldest offset of %s/%d: %w", dlq.topic, partition, err)
+	}
+	next, err := client.GetOffset(dlq.topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return fmt.Errorf("failed to read newest offset of %s/%d: %w", dlq.topic, partition, err)
+	}
+	if oldest >= next {
+		return nil
+	}
+	pc, err := consumer.ConsumePartition(dlq.topic, partition, oldest)
+	if err != nil {
+		return fmt.Errorf("failed to consume %s/%d: %w", dlq.topic, partition, err)
+	}
+	defer pc.Close()
+	for {
+		select {
+		case msg := <-pc.Messages():
+			if err := fn(toDeadLetter(msg)); err != nil {
+				return err
+			}
+			if msg.Offset >= next-1 {
+				return nil
+			}
+		case err := <-pc.Errors():
+			return fmt.Errorf("failed to read %s/%d: %w", dlq.topic, partition, err)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Redrive publishes dl to topic as it was originally received. When topic is empty, it goes back to the topic it was
+// first read from, before any retry. Its attempts are kept, so a new failure adds to them, but it goes through the
+// retry topics again.
+func (dlq *DeadLetterQueue) Redrive(dl *DeadLetter, topic string) error {
+	if topic == "" && len(dl.Attempts) > 0 {
+		topic = dl.Attempts[0].Topic
+	}
+	if topic == "" {
+		topic = dl.OriginalTopic
+	}
+	if topic == "" {
+		return errors.New("dead letter has no original topic")
+	}
+	headers := originalHeaders(dl.msg.Headers)
+	if attempts := header(dl.msg.Headers, HeaderAttempts); attempts != "" {
+		headers = append(headers, sarama.RecordHeader{Key: []byte(HeaderAttempts), Value: []byte(attempts)})
+	}
+	if err := dlq.producer.SendMessage(forward(topic, dl.msg, headers)); err != nil {
+		return fmt.Errorf("failed to redrive %d/%d to %s: %w", dl.Partition, dl.Offset, topic, err)
+	}
+	return nil
+}
+
+// Topic is the dead-letter topic.
+func (dlq *DeadLetterQueue) Topic() string {
+	return dlq.topic
+}
+
+func toDeadLetter(msg *sarama.ConsumerMessage) *DeadLetter {
+	dl := &DeadLetter{
+		Partition:     msg.Partition,
+		Offset:        msg.Offset,
+		Key:           string(msg.Key),
+		Class:         header(msg.Headers, HeaderErrorClass),
+		Message:       header(msg.Headers, HeaderErrorMessage),
+		Attempts:      attemptsOf(msg.Headers),
+		OriginalTopic: header(msg.Headers, HeaderOriginalTopic),
+		Headers:       map[string]string{},
+		Value:         string(msg.Value),
+		msg:           msg,
+	}
+	if details := header(msg.Headers, HeaderErrorDetails); json.Valid([]byte(details)) {
+		dl.Details = json.RawMessage(details)
+	}
+	if p, err := strconv.ParseInt(header(msg.Headers, HeaderOriginalPartition), 10, 32); err == nil {
+		dl.OriginalPartition = int32(p)
+	}
+	if o, err := strconv.ParseInt(header(msg.Headers, HeaderOriginalOffset), 10, 64); err == nil {
+		dl.OriginalOffset = o
+	}
+	if t, err := time.Parse(time.RFC3339Nano, header(msg.Headers, HeaderOriginalTimestamp)); err == nil {
+		dl.OriginalTimestamp = t
+	}
+	for _, h := range originalHeaders(msg.Headers) {
+		dl.Headers[string(h.Key)] = string(h.Value)
+	}
+	return dl
+}
+
+func forward(topic string, msg *sarama.ConsumerMessage, headers []sarama.RecordHeader) *sarama.ProducerMessage {
+	pm := &sarama.ProducerMessage{Topic: topic, Value: sarama.ByteEncoder(msg.Value), Headers: headers}
+	if msg.Key != nil {
+		pm.Key = sarama.ByteEncoder(msg.Key)
+	}
+	return pm
+}
+
+func errorClass(err error) string {
+	var he HandlerError
+	if errors.As(err, &he) && he.Class != "" {
+		return he.Class
+	}
+	return classUnknown
+}
+
+// errorDetails encodes the details of err, nil when it has none or they can't be encoded.
+func errorDetails(err error) []byte {
+	var he HandlerError
+	if !errors.As(err, &he) || he.Details == nil {
+		return nil
+	}
+	details, jerr := json.Marshal(he.Details)
+	if jerr != nil {
+		return nil
+	}
+	return details
+}
+
+// Retryable tells whether err is a transient failure, worth handling again later.
+func Retryable(err error) bool {
+	var he HandlerError
+	return errors.As(err, &he) && he.Retryable
+}
+
+// encodeAttempts returns the attempts of msg followed by the one that just failed with err, for the attempts header.
+func encodeAttempts(msg *sarama.ConsumerMessage, err error) ([]byte, error) {
+	attempts := append(attemptsOf(msg.Headers), Attempt{At: time.Now().UTC(), Topic: msg.Topic, Class: errorClass(err), Message: err.Error()})
+	encoded, jerr := json.Marshal(attempts)
+	if jerr != nil {
+		return nil, fmt.Errorf("failed to encode attempts: %w", jerr)
+	}
+	return encoded, nil
+}
+
+func attemptsOf(headers []*sarama.RecordHeader) []Attempt {
+	var attempts []Attempt
+	if v := header(headers, HeaderAttempts); v != "" {
+		_ = json.Unmarshal([]byte(v), &attempts)
+	}
+	return attempts
+}
+
+// origin returns the headers locating msg where it was first read. A message of a retry topic carries them from the
+// topic it was first read from, any other is located at its own position.
+func origin(msg *sarama.ConsumerMessage) []sarama.RecordHeader {
+	keys := []string{HeaderOriginalTopic, HeaderOriginalPartition, HeaderOriginalOffset, HeaderOriginalTimestamp}
+	if header(msg.Headers, HeaderOriginalTopic) != "" {
+		carried := make([]sarama.RecordHeader, 0, len(keys))
+		for _, key := range keys {
+			carried = append(carried, sarama.RecordHeader{Key: []byte(key), Value: []byte(header(msg.Headers, key))})
+		}
+		return carried
+	}
+	return []sarama.RecordHeader{
+		{Key: []byte(HeaderOriginalTopic), Value: []byte(msg.Topic)},
+		{Key: []byte(HeaderOriginalPartition), Value: []byte(strconv.Itoa(int(msg.Partition)))},
+		{Key: []byte(HeaderOriginalOffset), Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+		{Key: []byte(HeaderOriginalTimestamp), Value: []byte(msg.Timestamp.UTC().Format(time.RFC3339Nano))},
+	}
+}
+
+// originalHeaders returns the headers of the message as it was received, without the dead-letter and retry ones.
+func originalHeaders(headers []*sarama.RecordHeader) []sarama.RecordHeader {
+	var original []sarama.RecordHeader
+	for _, h := range headers {
+		if h == nil || strings.HasPrefix(string(h.Key), headerPrefix) || strings.HasPrefix(string(h.Key), retryHeaderPrefix) {
+			continue
+		}
+		original = append(original, *h)
+	}
+	return original
+}
+
+func header(headers []*sarama.RecordHeader, key string) string {
+	for _, h := range headers {
+		if h != nil && string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func NewDeadLetterQueue(sc *SaramaConfig, producer *Producer, log *zap.Logger) *DeadLetterQueue {
+	return &DeadLetterQueue{producer: producer, topic: sc.DeadLetterTopic, log: log}
+}
@@ -0,0 +1,121 @@
+package kafka
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"go.uber.org/zap/zaptest"
+)
+
+// recordingProducer captures the messages sent through a mock sync producer.
+func recordingProducer(t *testing.T, sent *[]*sarama.ProducerMessage, times int) *Producer {
+	raw := mocks.NewSyncProducer(t, nil)
+	for i := 0; i < times; i++ {
+		raw.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+			*sent = append(*sent, msg)
+			return nil
+		})
+	}
+	t.Cleanup(func() { _ = raw.Close() })
+	return &Producer{raw: raw}
+}
+
+// received turns a sent message back into the message a consumer of its topic reads.
+func received(t *testing.T, msg *sarama.ProducerMessage, offset int64) *sarama.ConsumerMessage {
+	var key []byte
+	if msg.Key != nil {
+		key, _ = msg.Key.Encode()
+	}
+	value, err := msg.Value.Encode()
+	if err != nil {
+		t.Fatalf("Failed to encode value: %v", err)
+	}
+	cm := &sarama.ConsumerMessage{Topic: msg.Topic, Offset: offset, Key: key, Value: value, Timestamp: time.Now()}
+	for i := range msg.Headers {
+		cm.Headers = append(cm.Headers, &msg.Headers[i])
+	}
+	return cm
+}
+
+func TestDeadLetterQueue_SendAndRedrive(t *testing.T) {
+	var sent []*sarama.ProducerMessage
+	dlq := &DeadLetterQueue{producer: recordingProducer(t, &sent, 3), topic: "payment-processing.dlq", log: zaptest.NewLogger(t)}
+	original := &sarama.ConsumerMessage{
+		Topic:     "payment-processing",
+		Partition: 2,
+		Offset:    42,
+		Key:       []byte("payment-789"),
+		Value:     []byte(`{"payment":{}}`),
+		Timestamp: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		Headers:   []*sarama.RecordHeader{{Key: []byte("ce_id"), Value: []byte("event-1")}},
+	}
+
+	if err := dlq.Send(original, HandlerError{Class: "history_unavailable", Err: errors.New("connection refused")}); err != nil {
+		t.Fatalf("Failed to dead-letter: %v", err)
+	}
+	dl := toDeadLetter(received(t, sent[0], 7))
+
+	if sent[0].Topic != dlq.topic || dl.Key != "payment-789" || dl.Value != `{"payment":{}}` {
+		t.Errorf("Expected the original message on the dead-letter topic, got %+v", dl)
+	}
+	if dl.Class != "history_unavailable" || dl.Message != "history_unavailable: connection refused" {
+		t.Errorf("Expected the error in the headers, got %s: %s", dl.Class, dl.Message)
+	}
+	if dl.OriginalTopic != "payment-processing" || dl.OriginalPartition != 2 || dl.OriginalOffset != 42 || !dl.OriginalTimestamp.Equal(original.Timestamp) {
+		t.Errorf("Expected the original position in the headers, got %+v", dl)
+	}
+	if len(dl.Attempts) != 1 || dl.Headers["ce_id"] != "event-1" {
+		t.Errorf("Expected one attempt and the original headers, got %+v", dl)
+	}
+
+	if err := dlq.Redrive(dl, ""); err != nil {
+		t.Fatalf("Failed to redrive: %v", err)
+	}
+	redriven := received(t, sent[1], 43)
+	if redriven.Topic != "payment-processing" || header(redriven.Headers, HeaderErrorClass) != "" || header(redriven.Headers, "ce_id") != "event-1" {
+		t.Errorf("Expected the message back on its topic without the dead-letter headers, got %+v", sent[1])
+	}
+
+	// A redriven message failing again keeps its history.
+	if err := dlq.Send(redriven, errors.New("boom")); err != nil {
+		t.Fatalf("Failed to dead-letter: %v", err)
+	}
+	again := toDeadLetter(received(t, sent[2], 8))
+	if len(again.Attempts) != 2 || again.Attempts[0].Class != "history_unavailable" || again.Class != classUnknown {
+		t.Errorf("Expected both attempts, got %+v", again.Attempts)
+	}
+	if again.OriginalOffset != 43 {
+		t.Errorf("Expected the offset of the redriven message, got %d", again.OriginalOffset)
+	}
+}
+
+func TestDeadLetterQueue_RedriveToTopic(t *testing.T) {
+	var sent []*sarama.ProducerMessage
+	dlq := &DeadLetterQueue{producer: recordingProducer(t, &sent, 1), topic: "payment-processing.dlq", log: zaptest.NewLogger(t)}
+	dl := toDeadLetter(&sarama.ConsumerMessage{Value: []byte("{}")})
+
+	if err := dlq.Redrive(dl, ""); err == nil {
+		t.Error("Expected an error without original topic")
+	}
+	if err := dlq.Redrive(dl, "payment-processing.replay"); err != nil || sent[0].Topic != "payment-processing.replay" {
+		t.Errorf("Expected the dead letter on the given topic, got %v", err)
+	}
+}
+
+func TestDeadLetterQueue_SendsErrorDetails(t *testing.T) {
+	var sent []*sarama.ProducerMessage
+	dlq := &DeadLetterQueue{producer: recordingProducer(t, &sent, 1), topic: "payment-processing.dlq", log: zaptest.NewLogger(t)}
+	original := &sarama.ConsumerMessage{Topic: "payment-processing", Value: []byte(`{"payment":{}}`)}
+	details := []map[string]string{{"field": "/payment/sellerInfo/sellerId", "message": "must not be empty"}}
+
+	if err := dlq.Send(original, HandlerError{Class: "schema_violation", Err: errors.New("invalid"), Details: details}); err != nil {
+		t.Fatalf("Failed to dead-letter: %v", err)
+	}
+	dl := toDeadLetter(received(t, sent[0], 0))
+	if string(dl.Details) != `[{"field":"/payment/sellerInfo/sellerId","message":"must not be empty"}]` {
+		t.Errorf("Expected the details in the headers, got %s", dl.Details)
+	}
+}
@@ -1,40 +1,94 @@
 package kafka
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	"github.com/IBM/sarama"
 	"github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
 	cloudevents "github.com/cloudevents/sdk-go/v2"
-	"log"
 )
 
-func NewCloudEventsKafkaConsumer(sc *SaramaConfig) (CloudEventsReceiver, error) {
-	saramaConfig := sarama.NewConfig()
-	saramaConfig.Version = sarama.V2_0_0_0
-	receiver, err := kafka_sarama.NewConsumer([]string{sc.Host}, saramaConfig, sc.GroupId, sc.PaymentProcessingTopic)
-	if err != nil {
-		log.Fatalf("failed to create protocol: %s", err.Error())
+// Producer owns the Kafka client used to publish score cards to the fraud detection topic.
+type Producer struct {
+	client   sarama.Client
+	protocol *kafka_sarama.Sender
+	sender   CloudEventsSender
+	// raw forwards received messages as they are, to the dead-letter topic.
+	raw   sarama.SyncProducer
+	topic string
+}
+
+// Check refreshes the metadata of the fraud detection topic, failing when no broker can answer for it.
+func (p *Producer) Check(ctx context.Context) error {
+	if p.client.Closed() {
+		return fmt.Errorf("kafka producer client is closed")
 	}
-	c, err := cloudevents.NewClient(receiver)
-	if err != nil {
-		log.Fatalf("failed to create client, %v", err)
+	done := make(chan error, 1)
+	go func() {
+		done <- p.client.RefreshMetadata(p.topic)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("kafka topic %s is not reachable: %w", p.topic, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	return c, nil
 }
 
-func NewCloudEventsKafkaSender(sc *SaramaConfig) (CloudEventsSender, error) {
-	saramaConfig := sarama.NewConfig()
-	saramaConfig.Version = sarama.V2_0_0_0
-	sender, err := kafka_sarama.NewSender([]string{sc.Host}, saramaConfig, sc.FraudDetectionTopic)
+// SendMessage publishes msg and waits for its ack.
+func (p *Producer) SendMessage(msg *sarama.ProducerMessage) error {
+	_, _, err := p.raw.SendMessage(msg)
+	return err
+}
+
+// Close waits for the messages being published and closes the Kafka client.
+func (p *Producer) Close(ctx context.Context) error {
+	err := errors.Join(p.protocol.Close(ctx), p.raw.Close())
+	if cerr := p.client.Close(); cerr != nil && !errors.Is(cerr, sarama.ErrClosedClient) {
+		err = errors.Join(err, cerr)
+	}
+	return err
+}
+
+func NewProducer(sc *SaramaConfig) (*Producer, error) {
+	config := newSaramaConfig()
+	// the sync producer behind the cloud events sender waits for every ack
+	config.Producer.Return.Successes = true
+	client, err := sarama.NewClient([]string{sc.Host}, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka producer client: %w", err)
+	}
+	sender, err := kafka_sarama.NewSenderFromClient(client, sc.FraudDetectionTopic)
 	if err != nil {
-		log.Fatalf("failed to create protocol: %s", err.Error())
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to create kafka sender: %w", err)
 	}
 	c, err := cloudevents.NewClient(sender, cloudevents.WithTimeNow(), cloudevents.WithUUIDs())
 	if err != nil {
-		log.Fatalf("failed to create client, %v", err)
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to create cloud events client: %w", err)
+	}
+	raw, err := sarama.NewSyncProducerFromClient(client)
+	if err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to create kafka producer: %w", err)
 	}
-	return c, nil
+	return &Producer{client: client, protocol: sender, sender: c, raw: raw, topic: sc.FraudDetectionTopic}, nil
 }
 
-type CloudEventsReceiver cloudevents.Client
+func NewCloudEventsKafkaSender(p *Producer) CloudEventsSender {
+	return p.sender
+}
+
+func newSaramaConfig() *sarama.Config {
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.Version = sarama.V2_0_0_0
+	return saramaConfig
+}
 
 type CloudEventsSender cloudevents.Client
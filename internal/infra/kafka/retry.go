@@ -0,0 +1,115 @@
+package kafka
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+// Headers of the messages of the retry topics.
+const (
+	retryHeaderPrefix    = "retry-"
+	HeaderRetryTier      = retryHeaderPrefix + "tier"
+	HeaderRetryNotBefore = retryHeaderPrefix + "not-before"
+)
+
+var retried = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "fraud_scoring_events_retried_total",
+	Help: "Messages sent to a retry topic per retry delay and error class.",
+}, []string{"delay", "class"})
+
+// RetryTier is a retry topic, its messages are handled again Delay after they failed.
+type RetryTier struct {
+	Topic string
+	Delay time.Duration
+	// Name is the delay as configured, such as 5m, used in the topic name and metrics.
+	Name string
+}
+
+// Retrier sends the messages that failed for a transient reason to the next retry tier. The tiers are consumed along
+// with the payment processing topic, each message being held until its delay is over.
+type Retrier struct {
+	producer *Producer
+	tiers    []RetryTier
+	log      *zap.Logger
+}
+
+// Send forwards msg to the retry tier after the one it was read from, adding err to its attempts and carrying where it
+// was first read for the dead-letter topic. It returns false
+// when msg went through every tier, it is then left to the dead-letter topic.
+func (r *Retrier) Send(msg *sarama.ConsumerMessage, err error) (bool, error) {
+	next := r.nextTier(msg)
+	if next >= len(r.tiers) {
+		return false, nil
+	}
+	tier := r.tiers[next]
+	attempts, aerr := encodeAttempts(msg, err)
+	if aerr != nil {
+		return false, aerr
+	}
+	headers := append(originalHeaders(msg.Headers),
+		sarama.RecordHeader{Key: []byte(HeaderAttempts), Value: attempts},
+		sarama.RecordHeader{Key: []byte(HeaderRetryTier), Value: []byte(strconv.Itoa(next))},
+		sarama.RecordHeader{Key: []byte(HeaderRetryNotBefore), Value: []byte(time.Now().Add(tier.Delay).UTC().Format(time.RFC3339Nano))},
+	)
+	headers = append(headers, origin(msg)...)
+	if err := r.producer.SendMessage(forward(tier.Topic, msg, headers)); err != nil {
+		return false, fmt.Errorf("failed to send message to retry topic %s: %w", tier.Topic, err)
+	}
+	retried.WithLabelValues(tier.Name, errorClass(err)).Inc()
+	r.log.Warn("message sent to retry topic",
+		zap.String("topic", msg.Topic),
+		zap.Int32("partition", msg.Partition),
+		zap.Int64("offset", msg.Offset),
+		zap.String("retry_topic", tier.Topic),
+		zap.Duration("delay", tier.Delay),
+		zap.Error(err))
+	return true, nil
+}
+
+// Topics are the retry topics, from the shortest delay to the longest.
+func (r *Retrier) Topics() []string {
+	topics := make([]string, len(r.tiers))
+	for i, tier := range r.tiers {
+		topics[i] = tier.Topic
+	}
+	return topics
+}
+
+// nextTier is the index of the tier msg goes to when it fails, the first one for a message of the payment processing
+// topic.
+func (r *Retrier) nextTier(msg *sarama.ConsumerMessage) int {
+	tier, err := strconv.Atoi(header(msg.Headers, HeaderRetryTier))
+	if err != nil {
+		return 0
+	}
+	return tier + 1
+}
+
+// notBefore is when msg may be handled, the zero time for a message that isn't waiting for a retry.
+func notBefore(msg *sarama.ConsumerMessage) time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, header(msg.Headers, HeaderRetryNotBefore))
+	return t
+}
+
+// newRetryTiers names a retry topic after prefix and each delay that parses, <prefix>.30s for instance.
+func newRetryTiers(prefix string, delays []string) []RetryTier {
+	var tiers []RetryTier
+	for _, name := range delays {
+		delay, err := time.ParseDuration(name)
+		if err != nil || delay <= 0 {
+			continue
+		}
+		tiers = append(tiers, RetryTier{Topic: prefix + "." + name, Delay: delay, Name: name})
+	}
+	return tiers
+}
+
+func NewRetrier(sc *SaramaConfig, producer *Producer, log *zap.Logger) *Retrier {
+	return &Retrier{producer: producer, tiers: sc.RetryTiers, log: log}
+}
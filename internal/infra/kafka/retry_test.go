@@ -0,0 +1,230 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestNewRetryTiers(t *testing.T) {
+	tiers := newRetryTiers("payment-processing.retry", []string{"30s", "none", "5m", "-1m", "1h"})
+
+	expected := []RetryTier{
+		{Topic: "payment-processing.retry.30s", Delay: 30 * time.Second, Name: "30s"},
+		{Topic: "payment-processing.retry.5m", Delay: 5 * time.Minute, Name: "5m"},
+		{Topic: "payment-processing.retry.1h", Delay: time.Hour, Name: "1h"},
+	}
+	if len(tiers) != len(expected) {
+		t.Fatalf("Expected %d tiers, got %+v", len(expected), tiers)
+	}
+	for i := range expected {
+		if tiers[i] != expected[i] {
+			t.Errorf("Expected tier %d to be %+v, got %+v", i, expected[i], tiers[i])
+		}
+	}
+}
+
+func TestRetrier_SendGoesThroughTheTiers(t *testing.T) {
+	var sent []*sarama.ProducerMessage
+	producer := recordingProducer(t, &sent, 3)
+	retrier := &Retrier{producer: producer, tiers: newRetryTiers("payment-processing.retry", []string{"30s", "5m"}), log: zaptest.NewLogger(t)}
+	dlq := &DeadLetterQueue{producer: producer, topic: "payment-processing.dlq", log: zaptest.NewLogger(t)}
+	cause := HandlerError{Class: "history_unavailable", Retryable: true, Err: errors.New("deadline exceeded")}
+
+	msg := &sarama.ConsumerMessage{Topic: "payment-processing", Offset: 42, Key: []byte("payment-789"), Value: []byte("{}"),
+		Headers: []*sarama.RecordHeader{{Key: []byte("ce_id"), Value: []byte("event-1")}}}
+	for i, tier := range retrier.tiers {
+		before := time.Now()
+		ok, err := retrier.Send(msg, cause)
+		if !ok || err != nil {
+			t.Fatalf("Expected the message to go to %s, got %v, %v", tier.Topic, ok, err)
+		}
+		msg = received(t, sent[i], int64(i))
+		if msg.Topic != tier.Topic || header(msg.Headers, "ce_id") != "event-1" {
+			t.Errorf("Expected the original message on %s, got %+v", tier.Topic, sent[i])
+		}
+		if due := notBefore(msg); due.Before(before.Add(tier.Delay)) || due.After(time.Now().Add(tier.Delay)) {
+			t.Errorf("Expected the message to be held %s, due at %s", tier.Delay, due)
+		}
+	}
+
+	if ok, err := retrier.Send(msg, cause); ok || err != nil {
+		t.Fatalf("Expected the retries to be exhausted, got %v, %v", ok, err)
+	}
+	if err := dlq.Send(msg, cause); err != nil {
+		t.Fatalf("Failed to dead-letter: %v", err)
+	}
+	dead := received(t, sent[2], 0)
+	dl := toDeadLetter(dead)
+	if len(dl.Attempts) != 3 || dl.Attempts[0].Topic != "payment-processing" || dl.Attempts[2].Topic != "payment-processing.retry.5m" {
+		t.Errorf("Expected an attempt per topic, got %+v", dl.Attempts)
+	}
+	if header(dead.Headers, HeaderRetryTier) != "" {
+		t.Error("Expected no retry header on the dead letter")
+	}
+}
+
+func TestDeadLetterQueue_KeepsTheOriginalPositionThroughRetries(t *testing.T) {
+	var sent []*sarama.ProducerMessage
+	producer := recordingProducer(t, &sent, 2)
+	retrier := &Retrier{producer: producer, tiers: newRetryTiers("payment-processing.retry", []string{"30s"}), log: zaptest.NewLogger(t)}
+	dlq := &DeadLetterQueue{producer: producer, topic: "payment-processing.dlq", log: zaptest.NewLogger(t)}
+	cause := HandlerError{Class: "history_unavailable", Retryable: true, Err: errors.New("deadline exceeded")}
+	at := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	msg := &sarama.ConsumerMessage{Topic: "payment-processing", Partition: 2, Offset: 42, Timestamp: at, Value: []byte("{}")}
+	if ok, err := retrier.Send(msg, cause); !ok || err != nil {
+		t.Fatalf("Expected the message to go to the retry topic, got %v, %v", ok, err)
+	}
+	retried := received(t, sent[0], 7)
+	if err := dlq.Send(retried, cause); err != nil {
+		t.Fatalf("Failed to dead-letter: %v", err)
+	}
+
+	dl := toDeadLetter(received(t, sent[1], 0))
+	if dl.OriginalTopic != "payment-processing" || dl.OriginalPartition != 2 || dl.OriginalOffset != 42 || !dl.OriginalTimestamp.Equal(at) {
+		t.Errorf("Expected the position on the payment processing topic, got %s/%d/%d at %s",
+			dl.OriginalTopic, dl.OriginalPartition, dl.OriginalOffset, dl.OriginalTimestamp)
+	}
+	for _, key := range []string{HeaderOriginalTopic, HeaderOriginalPartition, HeaderOriginalOffset, HeaderOriginalTimestamp} {
+		var n int
+		for _, h := range sent[1].Headers {
+			if string(h.Key) == key {
+				n++
+			}
+		}
+		if n != 1 {
+			t.Errorf("Expected one %s header on the dead letter, got %d", key, n)
+		}
+	}
+}
+
+func TestDeadLetterQueue_RedriveToFirstTopic(t *testing.T) {
+	var sent []*sarama.ProducerMessage
+	dlq := &DeadLetterQueue{producer: recordingProducer(t, &sent, 1), topic: "payment-processing.dlq", log: zaptest.NewLogger(t)}
+	dl := &DeadLetter{
+		OriginalTopic: "payment-processing.retry.1h",
+		Attempts:      []Attempt{{Topic: "payment-processing"}, {Topic: "payment-processing.retry.1h"}},
+		msg: &sarama.ConsumerMessage{Value: []byte("{}"), Headers: []*sarama.RecordHeader{
+			{Key: []byte(HeaderRetryTier), Value: []byte("2")},
+			{Key: []byte(HeaderRetryNotBefore), Value: []byte(time.Now().Format(time.RFC3339Nano))},
+		}},
+	}
+
+	if err := dlq.Redrive(dl, ""); err != nil {
+		t.Fatalf("Failed to redrive: %v", err)
+	}
+	redriven := received(t, sent[0], 0)
+	if redriven.Topic != "payment-processing" {
+		t.Errorf("Expected the dead letter back on the topic it was first read from, got %s", redriven.Topic)
+	}
+	if header(redriven.Headers, HeaderRetryTier) != "" || !notBefore(redriven).IsZero() {
+		t.Error("Expected the redriven message to go through the retries again")
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	if !Retryable(HandlerError{Class: "scoring_timeout", Retryable: true, Err: errors.New("deadline exceeded")}) {
+		t.Error("Expected a retryable handler error to be retried")
+	}
+	if Retryable(HandlerError{Class: "invalid_data", Err: errors.New("bad json")}) || Retryable(errors.New("boom")) {
+		t.Error("Expected terminal and unclassified errors not to be retried")
+	}
+}
+
+// pausingGroup records the partitions paused and resumed.
+type pausingGroup struct {
+	sarama.ConsumerGroup
+	mu     sync.Mutex
+	paused map[string][]int32
+}
+
+func (pg *pausingGroup) Pause(partitions map[string][]int32) {
+	pg.mu.Lock()
+	defer pg.mu.Unlock()
+	pg.paused = partitions
+}
+
+func (pg *pausingGroup) Resume(map[string][]int32) {
+	pg.mu.Lock()
+	defer pg.mu.Unlock()
+	pg.paused = nil
+}
+
+func (pg *pausingGroup) pausedPartitions() map[string][]int32 {
+	pg.mu.Lock()
+	defer pg.mu.Unlock()
+	return pg.paused
+}
+
+func TestConsumerGroup_HoldPausesRetryPartitionUntilDue(t *testing.T) {
+	group := &pausingGroup{}
+	cg := &ConsumerGroup{
+		group:        group,
+		backpressure: newBackpressure(time.Second, zaptest.NewLogger(t)),
+		handling:     context.Background(),
+		delayed:      map[topicPartition]struct{}{},
+		log:          zaptest.NewLogger(t),
+	}
+	due := time.Now().Add(100 * time.Millisecond)
+	msg := &sarama.ConsumerMessage{
+		Topic:     "payment-processing.retry.30s",
+		Partition: 2,
+		Headers: []*sarama.RecordHeader{
+			{Key: []byte(HeaderRetryNotBefore), Value: []byte(due.Format(time.RFC3339Nano))},
+		},
+	}
+
+	held := make(chan bool)
+	go func() {
+		held <- cg.hold(newFakeSession(context.Background()), msg)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	if paused := group.pausedPartitions(); len(paused[msg.Topic]) != 1 || paused[msg.Topic][0] != 2 {
+		t.Errorf("Expected the retry partition to be paused until the message is due, got %v", paused)
+	}
+	if delayed := cg.delayedPartitions(); len(delayed[msg.Topic]) != 1 {
+		t.Errorf("Expected the retry partition to be kept paused by the backpressure, got %v", delayed)
+	}
+
+	if !<-held {
+		t.Fatal("Expected the message to be handed over once due")
+	}
+	if time.Now().Before(due) {
+		t.Error("Expected the message to be held until due")
+	}
+	if paused := group.pausedPartitions(); paused != nil {
+		t.Errorf("Expected the retry partition to be resumed, got %v", paused)
+	}
+}
+
+func TestConsumerGroup_HoldGivesUpWithTheSession(t *testing.T) {
+	group := &pausingGroup{}
+	cg := &ConsumerGroup{
+		group:        group,
+		backpressure: newBackpressure(time.Second, zaptest.NewLogger(t)),
+		handling:     context.Background(),
+		delayed:      map[topicPartition]struct{}{},
+		log:          zaptest.NewLogger(t),
+	}
+	msg := &sarama.ConsumerMessage{
+		Topic: "payment-processing.retry.1h",
+		Headers: []*sarama.RecordHeader{
+			{Key: []byte(HeaderRetryNotBefore), Value: []byte(time.Now().Add(time.Hour).Format(time.RFC3339Nano))},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if cg.hold(newFakeSession(ctx), msg) {
+		t.Error("Expected the message to be given up once the session ended")
+	}
+	if len(cg.delayedPartitions()) != 0 {
+		t.Error("Expected no partition left delayed")
+	}
+}
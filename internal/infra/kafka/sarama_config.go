@@ -1,19 +1,45 @@
 package kafka
 
-import "os"
+import (
+	"fraud-scoring/internal/infra/env"
+	"os"
+	"time"
+)
 
 type SaramaConfig struct {
 	Host                   string
 	PaymentProcessingTopic string
 	FraudDetectionTopic    string
 	GroupId                string
+	// StallTimeout is how long a message may stay in its handler before the consumer is reported as not live.
+	StallTimeout time.Duration
+	// Workers is how many events are handled at the same time, QueueSize how many wait for each worker.
+	Workers   int
+	QueueSize int
+	// PauseCheckInterval is how often the dependencies are checked, to pause or resume the consumption, and the lag
+	// of the assigned partitions reported.
+	PauseCheckInterval time.Duration
+	// DeadLetterTopic receives the messages that could not be handled, <payment processing topic>.dlq by default.
+	DeadLetterTopic string
+	// RetryTiers are the retry topics of the transient failures, from the shortest delay to the longest.
+	RetryTiers []RetryTier
 }
 
 func NewSaramaConfig() *SaramaConfig {
-	return &SaramaConfig{
+	sc := &SaramaConfig{
 		Host:                   os.Getenv("KAFKA_HOST"),
 		PaymentProcessingTopic: os.Getenv("KAFKA_PAYMENT_PROCESSING_TOPIC"),
 		FraudDetectionTopic:    os.Getenv("KAFKA_FRAUD_DETECTION_TOPIC"),
 		GroupId:                os.Getenv("KAFKA_GROUP_ID"),
+		StallTimeout:           env.Duration("KAFKA_CONSUMER_STALL_TIMEOUT", time.Minute),
+		Workers:                max(env.Int("KAFKA_CONSUMER_WORKERS", 8), 1),
+		QueueSize:              max(env.Int("KAFKA_CONSUMER_QUEUE_SIZE", 64), 1),
+		PauseCheckInterval:     max(env.Duration("KAFKA_CONSUMER_PAUSE_CHECK_INTERVAL", 5*time.Second), 100*time.Millisecond),
 	}
+	sc.DeadLetterTopic = env.String("KAFKA_DEAD_LETTER_TOPIC", sc.PaymentProcessingTopic+".dlq")
+	sc.RetryTiers = newRetryTiers(
+		env.String("KAFKA_RETRY_TOPIC_PREFIX", sc.PaymentProcessingTopic+".retry"),
+		env.Strings("KAFKA_RETRY_DELAYS", []string{"30s", "5m", "1h"}),
+	)
+	return sc
 }
@@ -0,0 +1,110 @@
+package kafka
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/IBM/sarama"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var eventsQueued = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "fraud_scoring_events_queued",
+	Help: "Events read from Kafka waiting for a worker.",
+})
+
+// OrderingKey tells which events are handled in the order they were read: the ones with the same key. Events with
+// different keys are handled concurrently. An empty key falls back to the key of the message.
+type OrderingKey func(event cloudevents.Event) string
+
+// job is a message read from a claim, on its way to a worker.
+type job struct {
+	session sarama.ConsumerGroupSession
+	msg     *sarama.ConsumerMessage
+	event   *cloudevents.Event
+	// err is why msg could not be read as an event, the job is then forwarded without being handled.
+	err     error
+	offsets *partitionOffsets
+
+	settled  bool
+	consumed bool
+}
+
+// workerPool runs the handlers on a fixed number of workers, each with a bounded queue. The jobs with the same key go
+// to the same worker, so they are handled one after the other in the order they were dispatched. A full queue blocks
+// the claims that feed it, holding back the consumption rather than buffering without bounds.
+type workerPool struct {
+	queues []chan *job
+	wg     sync.WaitGroup
+}
+
+// dispatch queues j on the worker of key. It returns false when ctx is done first.
+func (wp *workerPool) dispatch(ctx context.Context, key string, j *job) bool {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	select {
+	case wp.queues[h.Sum32()%uint32(len(wp.queues))] <- j:
+		eventsQueued.Inc()
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// stop waits for the workers to empty their queues. Nothing may be dispatched anymore.
+func (wp *workerPool) stop() {
+	for _, queue := range wp.queues {
+		close(queue)
+	}
+	wp.wg.Wait()
+}
+
+// partitionOffsets marks the messages of a claim as consumed in offset order: a message is marked once it and all
+// the messages read before it from the partition are settled. Past a message left unconsumed nothing is marked
+// anymore, so it is read again, along with the ones after it, by the next owner of the partition.
+type partitionOffsets struct {
+	mu        sync.Mutex
+	session   sarama.ConsumerGroupSession
+	pending   []*job
+	stuck     bool
+	unsettled sync.WaitGroup
+	// next is the offset the partition is committed at, negative until known.
+	next atomic.Int64
+}
+
+func (po *partitionOffsets) add(j *job) {
+	po.mu.Lock()
+	defer po.mu.Unlock()
+	po.pending = append(po.pending, j)
+	po.unsettled.Add(1)
+}
+
+// settle records that j was handled, or given up when it isn't consumed, and marks the messages it was holding back.
+func (po *partitionOffsets) settle(j *job, consumed bool) {
+	defer po.unsettled.Done()
+	po.mu.Lock()
+	defer po.mu.Unlock()
+	j.settled, j.consumed = true, consumed
+	var last *sarama.ConsumerMessage
+	for len(po.pending) > 0 && po.pending[0].settled {
+		head := po.pending[0]
+		po.pending = po.pending[1:]
+		po.stuck = po.stuck || !head.consumed
+		if !po.stuck {
+			last = head.msg
+		}
+	}
+	if last != nil {
+		po.session.MarkMessage(last, "")
+		po.next.Store(last.Offset + 1)
+	}
+}
+
+// wait returns once every message added is settled.
+func (po *partitionOffsets) wait() {
+	po.unsettled.Wait()
+}
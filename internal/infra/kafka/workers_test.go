@@ -0,0 +1,162 @@
+package kafka
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap/zaptest"
+)
+
+// fakeSession records the offsets marked in a consumer group session.
+type fakeSession struct {
+	ctx    context.Context
+	mu     sync.Mutex
+	marked map[int32]int64
+}
+
+func newFakeSession(ctx context.Context) *fakeSession {
+	return &fakeSession{ctx: ctx, marked: map[int32]int64{}}
+}
+
+func (fs *fakeSession) Claims() map[string][]int32               { return nil }
+func (fs *fakeSession) MemberID() string                         { return "member" }
+func (fs *fakeSession) GenerationID() int32                      { return 1 }
+func (fs *fakeSession) MarkOffset(string, int32, int64, string)  {}
+func (fs *fakeSession) Commit()                                  {}
+func (fs *fakeSession) ResetOffset(string, int32, int64, string) {}
+func (fs *fakeSession) Context() context.Context                 { return fs.ctx }
+func (fs *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, _ string) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.marked[msg.Partition] = msg.Offset
+}
+
+func (fs *fakeSession) markedOffset(partition int32) (int64, bool) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	offset, ok := fs.marked[partition]
+	return offset, ok
+}
+
+func TestPartitionOffsets_MarksSettledPrefix(t *testing.T) {
+	session := newFakeSession(context.Background())
+	offsets := &partitionOffsets{session: session}
+	jobs := make([]*job, 4)
+	for i := range jobs {
+		jobs[i] = &job{msg: &sarama.ConsumerMessage{Offset: int64(i)}}
+		offsets.add(jobs[i])
+	}
+
+	offsets.settle(jobs[1], true)
+	if _, ok := session.markedOffset(0); ok {
+		t.Fatal("Expected nothing marked while the first message is being handled")
+	}
+	offsets.settle(jobs[0], true)
+	if offset, _ := session.markedOffset(0); offset != 1 {
+		t.Fatalf("Expected offset 1 marked, got %d", offset)
+	}
+	if next := offsets.next.Load(); next != 2 {
+		t.Errorf("Expected the partition committed at 2, got %d", next)
+	}
+	// A message left unconsumed holds back the ones after it
+	offsets.settle(jobs[2], false)
+	offsets.settle(jobs[3], true)
+	if offset, _ := session.markedOffset(0); offset != 1 {
+		t.Errorf("Expected offset 1 to stay marked, got %d", offset)
+	}
+	offsets.wait()
+}
+
+func TestConsumerGroup_KeepsOrderPerKey(t *testing.T) {
+	handling, abort := context.WithCancel(context.Background())
+	defer abort()
+	var mu sync.Mutex
+	handled := map[string][]int{}
+	cg := &ConsumerGroup{
+		workerCount: 4,
+		queueSize:   2,
+		log:         zaptest.NewLogger(t),
+		handling:    handling,
+		inFlight:    map[messagePosition]time.Time{},
+		key: func(event cloudevents.Event) string {
+			return event.Subject()
+		},
+		handler: func(_ context.Context, event cloudevents.Event) error {
+			seq, _ := strconv.Atoi(event.ID())
+			// The first events of a key take longer, the ones behind them must still wait
+			time.Sleep(time.Duration(10-seq%10) * time.Millisecond)
+			mu.Lock()
+			defer mu.Unlock()
+			handled[event.Subject()] = append(handled[event.Subject()], seq)
+			return nil
+		},
+	}
+	cg.workers = cg.startWorkers()
+
+	session := newFakeSession(context.Background())
+	offsets := &partitionOffsets{session: session}
+	for seq := 0; seq < 30; seq++ {
+		event := cloudevents.NewEvent()
+		event.SetID(strconv.Itoa(seq))
+		event.SetSubject("buyer-" + strconv.Itoa(seq%3))
+		j := &job{session: session, msg: &sarama.ConsumerMessage{Offset: int64(seq)}, event: &event, offsets: offsets}
+		offsets.add(j)
+		if !cg.workers.dispatch(context.Background(), cg.keyOf(j), j) {
+			t.Fatal("Expected the job to be dispatched")
+		}
+	}
+	offsets.wait()
+	cg.workers.stop()
+
+	for key, seqs := range handled {
+		if len(seqs) != 10 {
+			t.Errorf("Expected 10 events of %s, got %v", key, seqs)
+		}
+		for i := 1; i < len(seqs); i++ {
+			if seqs[i] < seqs[i-1] {
+				t.Errorf("Expected the events of %s in order, got %v", key, seqs)
+				break
+			}
+		}
+	}
+	if offset, _ := session.markedOffset(0); offset != 29 {
+		t.Errorf("Expected the last offset marked, got %d", offset)
+	}
+}
+
+func TestConsumerGroup_GivesUpJobsOfEndedSession(t *testing.T) {
+	handling, abort := context.WithCancel(context.Background())
+	defer abort()
+	cg := &ConsumerGroup{
+		workerCount: 1,
+		queueSize:   1,
+		log:         zaptest.NewLogger(t),
+		handling:    handling,
+		inFlight:    map[messagePosition]time.Time{},
+		handler: func(context.Context, cloudevents.Event) error {
+			t.Error("Expected no event of an ended session to be handled")
+			return nil
+		},
+	}
+	cg.workers = cg.startWorkers()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	session := newFakeSession(ctx)
+	offsets := &partitionOffsets{session: session}
+	event := cloudevents.NewEvent()
+	j := &job{session: session, msg: &sarama.ConsumerMessage{}, event: &event, offsets: offsets}
+	offsets.add(j)
+	cg.workers.queues[0] <- j
+	eventsQueued.Inc()
+	offsets.wait()
+	cg.workers.stop()
+
+	if _, ok := session.markedOffset(0); ok {
+		t.Error("Expected the message to be left for the next owner of the partition")
+	}
+}
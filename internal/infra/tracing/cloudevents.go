@@ -0,0 +1,52 @@
+package tracing
+
+import (
+	"context"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// eventCarrier reads and writes trace context as CloudEvents extension attributes, as the distributed tracing
+// extension does with traceparent and tracestate.
+type eventCarrier struct {
+	event *cloudevents.Event
+}
+
+func (c eventCarrier) Get(key string) string {
+	v, ok := c.event.Extensions()[key]
+	if !ok {
+		return ""
+	}
+	s, err := types.ToString(v)
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+func (c eventCarrier) Set(key, value string) {
+	c.event.SetExtension(key, value)
+}
+
+func (c eventCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.event.Extensions()))
+	for k := range c.event.Extensions() {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+var _ propagation.TextMapCarrier = eventCarrier{}
+
+// Extract returns ctx carrying the trace context of event, if it has one.
+func Extract(ctx context.Context, event *cloudevents.Event) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, eventCarrier{event: event})
+}
+
+// Inject writes the trace context of ctx in the extension attributes of event.
+func Inject(ctx context.Context, event *cloudevents.Event) {
+	otel.GetTextMapPropagator().Inject(ctx, eventCarrier{event: event})
+}
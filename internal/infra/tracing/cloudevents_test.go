@@ -0,0 +1,41 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInjectExtract(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer("test").Start(context.Background(), "publish")
+	defer span.End()
+
+	e := cloudevents.NewEvent()
+	Inject(ctx, &e)
+	if e.Extensions()["traceparent"] == nil {
+		t.Fatalf("Expected a traceparent extension, got %v", e.Extensions())
+	}
+
+	// the extension survives the wire as a string attribute
+	received := cloudevents.NewEvent()
+	received.SetExtension("traceparent", e.Extensions()["traceparent"])
+	got := trace.SpanContextFromContext(Extract(context.Background(), &received))
+	if got.TraceID() != span.SpanContext().TraceID() || got.SpanID() != span.SpanContext().SpanID() || !got.IsRemote() {
+		t.Errorf("Expected the remote span context %v, got %v", span.SpanContext(), got)
+	}
+}
+
+func TestExtractWithoutTraceContext(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	e := cloudevents.NewEvent()
+	if sc := trace.SpanContextFromContext(Extract(context.Background(), &e)); sc.IsValid() {
+		t.Errorf("Expected no span context, got %v", sc)
+	}
+}
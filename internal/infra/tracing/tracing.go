@@ -0,0 +1,109 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"fraud-scoring/internal/infra/env"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.uber.org/zap"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+	ExporterOTLP   = "otlp"
+)
+
+type Config struct {
+	// Exporter is "none", "stdout", "file" or "otlp". The OTLP exporter is configured through the standard
+	// OTEL_EXPORTER_OTLP_* variables.
+	Exporter string
+	// File receives the spans of the file exporter, one JSON document per span.
+	File        string
+	SampleRatio float64
+	ServiceName string
+	Version     string
+}
+
+// Provider owns the tracer provider installed as the global one.
+type Provider struct {
+	tp     *sdktrace.TracerProvider
+	closer io.Closer
+}
+
+// Shutdown exports the spans still buffered and releases the exporter.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	err := p.tp.Shutdown(ctx)
+	if p.closer != nil {
+		if cerr := p.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// NewProvider installs the global tracer provider and the W3C trace context propagator. With the "none" exporter
+// spans are still created, so trace context is propagated, but never exported.
+func NewProvider(config *Config, log *zap.Logger) (*Provider, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(config.ServiceName),
+		semconv.ServiceVersion(config.Version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build tracing resource: %w", err)
+	}
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	}
+	p := &Provider{}
+	var exporter sdktrace.SpanExporter
+	switch config.Exporter {
+	case ExporterNone:
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterFile:
+		var f *os.File
+		f, err = os.OpenFile(config.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open trace file %q: %w", config.File, err)
+		}
+		p.closer = f
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(f))
+	case ExporterOTLP:
+		exporter, err = otlptracegrpc.New(context.Background())
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", config.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", config.Exporter, err)
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	p.tp = sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(p.tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	log.Info("tracing configured", zap.String("exporter", config.Exporter), zap.Float64("sample_ratio", config.SampleRatio))
+	return p, nil
+}
+
+func NewConfig() *Config {
+	return &Config{
+		Exporter:    env.String("TRACING_EXPORTER", ExporterNone),
+		File:        env.String("TRACING_FILE", "traces.json"),
+		SampleRatio: env.Float("TRACING_SAMPLE_RATIO", 1),
+		ServiceName: env.String("TRACING_SERVICE_NAME", "fraud-scoring"),
+		Version:     env.String("SERVICE_VERSION", "dev"),
+	}
+}